@@ -40,11 +40,18 @@ output "all_bases" {
 
 # Only returns "Sample Dataset" database
 output "id" {
-  value = {
-    for base in data.metabase_bases.all.databases :
-    base.name => base.id
-    if base.name == var.base_name
-  }
+  value = data.metabase_bases.all.names_to_ids[var.base_name]
+}
+
+# Returns PostgreSQL databases with their tables
+data "metabase_bases" "postgres" {
+  engine         = "postgres"
+  include_sample = false
+  include_tables = true
+}
+
+output "postgres_ids" {
+  value = data.metabase_bases.postgres.ids
 }
 
 # Returns parameters for the database with id=1
@@ -56,6 +63,20 @@ output "base_name" {
   value = data.metabase_base.one
 }
 
+# Returns parameters for the database looked up by its name
+data "metabase_base" "sample" {
+  name           = var.base_name
+  include_tables = true
+}
+
+output "sample_engine" {
+  value = data.metabase_base.sample.engine
+}
+
+output "sample_tables" {
+  value = [for table in data.metabase_base.sample.tables : table.name]
+}
+
 # Creates a new database
 resource "metabase_database" "my" {
   name     = "test"
@@ -70,3 +91,340 @@ resource "metabase_database" "my" {
 output "my_id" {
   value = metabase_database.my.id
 }
+
+# Creates a new permissions group
+resource "metabase_permissions_group" "analysts" {
+  name = "Analysts"
+}
+
+# Resolves the ID of a built-in group
+data "metabase_permissions_group" "all_users" {
+  name = "All Users"
+}
+
+# Creates a new user, destroying it deactivates the user
+resource "metabase_user" "analyst" {
+  email      = "analyst@example.com"
+  first_name = "Jane"
+  last_name  = "Doe"
+  locale     = "en"
+
+  login_attributes = {
+    region = "emea"
+  }
+}
+
+# Adds a single user to a group, other members are kept
+resource "metabase_permissions_group_membership" "analyst" {
+  group_id = metabase_permissions_group.analysts.id
+  user_id  = metabase_user.analyst.id
+}
+
+# Manages the full member list of a group, other members are removed
+resource "metabase_permissions_group" "engineers" {
+  name = "Engineers"
+}
+
+resource "metabase_permissions_group_members" "engineers" {
+  group_id = metabase_permissions_group.engineers.id
+  user_ids = [metabase_user.analyst.id]
+}
+
+# Gives analysts unrestricted access to the new database, including SQL
+resource "metabase_database_permissions" "analysts_my" {
+  group_id    = metabase_permissions_group.analysts.id
+  database_id = metabase_database.my.id
+  access      = "unrestricted"
+  native      = "write"
+}
+
+# Creates a collection tree, destroying a collection archives it
+resource "metabase_collection" "analytics" {
+  name        = "Analytics"
+  description = "Company-wide analytics"
+  official    = true
+}
+
+resource "metabase_collection" "kpis" {
+  name      = "KPIs"
+  parent_id = metabase_collection.analytics.id
+}
+
+# Lets analysts curate the KPIs collection
+resource "metabase_collection_permissions" "analysts_kpis" {
+  group_id      = metabase_permissions_group.analysts.id
+  collection_id = metabase_collection.kpis.id
+  permission    = "write"
+}
+
+# Saves a native SQL question in the KPIs collection
+resource "metabase_card" "signups" {
+  name          = "Daily signups"
+  collection_id = metabase_collection.kpis.id
+  display       = "line"
+
+  dataset_query = jsonencode({
+    type     = "native"
+    database = tonumber(metabase_database.my.id)
+    native = {
+      query = "SELECT date_trunc('day', created_at) AS day, count(*) FROM users GROUP BY 1"
+    }
+  })
+
+  visualization_settings = jsonencode({
+    "graph.dimensions" = ["day"]
+    "graph.metrics"    = ["count"]
+    "graph.show_goal"  = true
+    "graph.goal_value" = 100
+  })
+}
+
+# Lays out the signups question on a dashboard with a date filter
+resource "metabase_dashboard" "kpis" {
+  name          = "KPIs"
+  collection_id = metabase_collection.kpis.id
+
+  parameters = jsonencode([
+    {
+      id   = "date"
+      name = "Date"
+      slug = "date"
+      type = "date/all-options"
+    },
+  ])
+
+  tab {
+    name = "Growth"
+  }
+
+  card {
+    card_id = metabase_card.signups.id
+    row     = 0
+    col     = 0
+    size_x  = 12
+    size_y  = 6
+
+    parameter_mappings = jsonencode([
+      {
+        parameter_id = "date"
+        card_id      = tonumber(metabase_card.signups.id)
+        target       = ["dimension", ["template-tag", "day"]]
+      },
+    ])
+  }
+}
+
+# Sends the dashboard every Monday by email and to Slack
+resource "metabase_dashboard_subscription" "weekly_kpis" {
+  dashboard_id  = metabase_dashboard.kpis.id
+  skip_if_empty = true
+
+  email {
+    user_ids  = [metabase_user.analyst.id]
+    addresses = ["leadership@example.com"]
+  }
+
+  slack {
+    channel = "#kpis"
+  }
+
+  schedule {
+    type = "weekly"
+    day  = "mon"
+    hour = 8
+  }
+
+  parameters = jsonencode([
+    {
+      id    = "date"
+      value = "past7days"
+    },
+  ])
+
+  depends_on = [metabase_slack_settings.slack]
+}
+
+# Alerts analysts when signups drop below the goal of the question
+resource "metabase_alert" "no_signups" {
+  card_id    = metabase_card.signups.id
+  condition  = "goal_below"
+  first_only = false
+
+  email {
+    user_ids = [metabase_user.analyst.id]
+  }
+
+  schedule {
+    type = "daily"
+    hour = 9
+  }
+}
+
+# Returns the schemas and tables of the sample database
+data "metabase_schemas" "sample" {
+  database_id = data.metabase_base.sample.id
+}
+
+data "metabase_tables" "sample_public" {
+  database_id = data.metabase_base.sample.id
+  schema      = "PUBLIC"
+}
+
+output "sample_public_tables" {
+  value = data.metabase_tables.sample_public.names_to_ids
+}
+
+# Resolves a table and a field by name
+data "metabase_table" "orders" {
+  database_id = data.metabase_base.sample.id
+  schema      = "PUBLIC"
+  name        = "ORDERS"
+}
+
+data "metabase_field" "orders_total" {
+  database_id = data.metabase_base.sample.id
+  table       = "ORDERS"
+  name        = "TOTAL"
+}
+
+output "orders_total_type" {
+  value = data.metabase_field.orders_total.base_type
+}
+
+# Shares the definition of large orders, destroying it archives the segment
+resource "metabase_segment" "large_orders" {
+  table_id    = data.metabase_table.orders.id
+  name        = "Large orders"
+  description = "Orders over 100"
+
+  definition = jsonencode({
+    source-table = data.metabase_table.orders.id
+    filter       = [">", ["field", data.metabase_table.orders.fields_to_ids["TOTAL"], null], 100]
+  })
+
+  revision_message = "Raise the threshold to 100"
+}
+
+# Documents a synced table, destroying the resource keeps the metadata
+resource "metabase_table" "orders" {
+  database_id  = data.metabase_base.sample.id
+  schema       = "PUBLIC"
+  name         = "ORDERS"
+  display_name = "Orders"
+  description  = "One row per order, including cancelled ones"
+  entity_type  = "entity/TransactionTable"
+  caveats      = "Totals include taxes"
+  field_order  = "smart"
+}
+
+# Points the product foreign key of orders to products and displays product titles instead of IDs
+resource "metabase_field" "products_id" {
+  database_id   = data.metabase_base.sample.id
+  table         = "PRODUCTS"
+  name          = "ID"
+  semantic_type = "type/PK"
+}
+
+resource "metabase_field" "products_title" {
+  database_id   = data.metabase_base.sample.id
+  table         = "PRODUCTS"
+  name          = "TITLE"
+  semantic_type = "type/Title"
+}
+
+resource "metabase_field" "orders_product_id" {
+  database_id        = data.metabase_base.sample.id
+  schema             = metabase_table.orders.schema
+  table              = metabase_table.orders.name
+  name               = "PRODUCT_ID"
+  display_name       = "Product"
+  semantic_type      = "type/FK"
+  fk_target_field_id = metabase_field.products_id.id
+
+  remapping {
+    field_id = metabase_field.products_title.id
+  }
+}
+
+# Remaps rating codes to labels
+resource "metabase_field" "reviews_rating" {
+  database_id      = data.metabase_base.sample.id
+  table            = "REVIEWS"
+  name             = "RATING"
+  semantic_type    = "type/Category"
+  has_field_values = "list"
+
+  remapping {
+    name = "Rating label"
+    values = {
+      "1" = "Poor"
+      "5" = "Excellent"
+    }
+  }
+}
+
+# Sets a single admin setting, destroying the resource resets it to its default
+resource "metabase_setting" "site_name" {
+  key   = "site-name"
+  value = jsonencode("Acme Analytics")
+}
+
+# Sets several admin settings, other settings are left as is
+resource "metabase_settings" "instance" {
+  values = {
+    "site-url"              = jsonencode(var.metabase_url)
+    "report-timezone"       = jsonencode("Europe/Berlin")
+    "anon-tracking-enabled" = jsonencode(false)
+    "humanization-strategy" = jsonencode("simple")
+    "enable-public-sharing" = jsonencode(false)
+  }
+}
+
+variable "smtp_password" {
+  type      = string
+  sensitive = true
+}
+
+# Configures the SMTP server, Metabase tests the connection on apply
+resource "metabase_email_settings" "smtp" {
+  host         = "smtp.example.com"
+  port         = 587
+  security     = "starttls"
+  username     = "metabase"
+  password     = var.smtp_password
+  from_address = "metabase@example.com"
+  from_name    = "Acme Analytics"
+  reply_to     = ["data-team@example.com"]
+}
+
+variable "slack_app_token" {
+  type      = string
+  sensitive = true
+}
+
+# Connects Metabase to a Slack app for subscriptions and alerts
+resource "metabase_slack_settings" "slack" {
+  app_token     = var.slack_app_token
+  files_channel = "metabase_files"
+}
+
+variable "ldap_bind_password" {
+  type      = string
+  sensitive = true
+}
+
+# Lets users log in with LDAP and syncs the analysts group from the directory
+resource "metabase_ldap_settings" "corporate" {
+  host       = "ldap.example.com"
+  port       = 636
+  security   = "ssl"
+  bind_dn    = "cn=metabase,ou=services,dc=example,dc=com"
+  password   = var.ldap_bind_password
+  user_base  = "ou=users,dc=example,dc=com"
+  group_sync = true
+
+  group_mapping {
+    dn        = "cn=analysts,ou=groups,dc=example,dc=com"
+    group_ids = [metabase_permissions_group.analysts.id]
+  }
+}
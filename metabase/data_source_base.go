@@ -2,101 +2,337 @@ package metabase
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceBase() *schema.Resource {
+	s := databaseSchema()
+	// never expose secrets of a single database
+	delete(s, "details_password")
+
+	lookupKeys := []string{"id", "name", "engine"}
+
+	s["id"] = &schema.Schema{
+		Description:  "Source ID",
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: lookupKeys,
+	}
+	s["name"] = &schema.Schema{
+		Description:  "Source name. Can be used instead of `id` to look up a database",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: lookupKeys,
+	}
+	s["engine"] = &schema.Schema{
+		Description:  "Engine of a source. Can be used instead of `id` to look up the only database of an engine",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: lookupKeys,
+	}
+	s["description"].Description = "Description of a source in Metabase"
+	s["include_tables"] = &schema.Schema{
+		Description: "Whether to return the tables of a source with their fields",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+	s["tables"] = &schema.Schema{
+		Description: "Tables of a source, only returned with `include_tables`",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: tableWithFieldsSchema(),
+		},
+	}
+	s["schedules"] = &schema.Schema{
+		Description: "Sync and scan schedules of a source",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cache_field_values": &schema.Schema{
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: scheduleSchema(),
+					},
+				},
+				"metadata_sync": &schema.Schema{
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: scheduleSchema(),
+					},
+				},
+			},
+		},
+	}
+
 	return &schema.Resource{
-		Description: "`metabase_base` data source can be used to retrieve parameters of a certain database.\n\n",
+		Description: "`metabase_base` data source can be used to retrieve parameters of a certain database.\n\n" +
+			"A database is looked up by exactly one of `id`, `name` or `engine`.",
 		ReadContext: dataSourceBaseRead,
-		Schema: map[string]*schema.Schema{
-			"description": &schema.Schema{
-				Description: "Description of a source in Metabase",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"id": &schema.Schema{
-				Description: "Source ID",
-				Type:        schema.TypeInt,
-				Required:    true,
-			},
-			"name": &schema.Schema{
-				Description: "Source name",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+		Schema:      s,
+	}
+}
+
+func tableWithFieldsSchema() map[string]*schema.Schema {
+	s := tableSchema()
+	s["fields"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: fieldSchema(),
+		},
+	}
+	return s
+}
+
+func fieldSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"display_name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"base_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"semantic_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"database_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"visibility_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"fk_target_field_id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+	}
+}
+
+func scheduleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"schedule_minute": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"schedule_day": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"schedule_frame": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"schedule_hour": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"schedule_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
 func dataSourceBaseRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
-	client := c.HTTPClient
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	dbId := strconv.Itoa(d.Get("id").(int))
+	var dbId string
+	if id, ok := d.GetOk("id"); ok {
+		dbId = strconv.Itoa(id.(int))
+	} else {
+		// exactly one of id, name and engine is set
+		attribute := "name"
+		if _, ok := d.GetOk("engine"); ok {
+			attribute = "engine"
+		}
+		id, lookupDiags := lookupDatabase(ctx, c, attribute, d.Get(attribute).(string))
+		if lookupDiags.HasError() {
+			return lookupDiags
+		}
+		dbId = strconv.Itoa(id)
+	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), nil)
+	database := &DatabaseRead{Database: &Database{}}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/database/%s", dbId), nil, database)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiags(err, fmt.Sprintf("Database %s not found", dbId))
 	}
+	database.Database.Details = database.Details
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Metabase-Session", c.Token)
+	if d.Get("include_tables").(bool) {
+		metadata, metadataDiags := readDatabaseMetadata(ctx, c, dbId)
+		if metadataDiags.HasError() {
+			return metadataDiags
+		}
+		database.Tables = metadata.Tables
+	}
 
-	r, err := client.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
+	flattened := flattenDatabase(*database.Database)
+	delete(flattened, "details_password")
+	flattened["tables"] = flattenTablesWithFields(database.Tables)
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign database attribute to d.%s in dataSourceBaseRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
 	}
-	defer r.Body.Close()
 
-	database := &DatabaseRead{}
-	err = json.NewDecoder(r.Body).Decode(&database)
-	if err != nil {
+	if err := d.Set("schedules", flattenSchedules(database.Schedules)); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Unable to decode JSON in dataSourceBaseRead()",
-			Detail:   "Unable to decode JSON in dataSourceBaseRead()",
+			Summary:  "Unable to assign database.Schedules to d.schedules in dataSourceBaseRead()",
+			Detail:   err.Error(),
 		})
 		return diags
 	}
 
-	if err := d.Set("id", database.Id); err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "Unable to set database.Id to d.id in dataSourceBaseRead()",
-			Detail:   "Unable to set database.Id to d.id in dataSourceBaseRead()",
-		})
-		return diags
+	d.SetId(strconv.Itoa(database.Id))
+
+	return diags
+}
+
+// readDatabaseMetadata returns a database with all its tables and their fields
+func readDatabaseMetadata(ctx context.Context, c *Client, dbId string) (*Database, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	database := &Database{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/database/%s/metadata", dbId), nil, database)
+	if err != nil {
+		return nil, apiErrorDiags(err, fmt.Sprintf("Database %s not found", dbId))
+	}
+
+	return database, diags
+}
+
+// lookupDatabase returns the ID of the only database whose attribute,
+// name or engine, has the value
+func lookupDatabase(ctx context.Context, c *Client, attribute, value string) (int, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var databases []Database
+	if err := c.getList(ctx, "/api/database", &databases); err != nil {
+		return 0, apiErrorDiags(err, "Databases not found")
+	}
+
+	var ids []string
+	var id int
+	for _, database := range databases {
+		actual := database.Name
+		if attribute == "engine" {
+			actual = database.Engine
+		}
+		if actual != value {
+			continue
+		}
+		id = database.Id
+		ids = append(ids, strconv.Itoa(database.Id))
 	}
 
-	if err := d.Set("name", database.Name); err != nil {
+	switch len(ids) {
+	case 0:
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Unable to assign database.Name to d.name in dataSourceBaseRead()",
-			Detail:   "Unable to assign database.Name to d.name in dataSourceBaseRead()",
+			Summary:  "Database not found",
+			Detail:   fmt.Sprintf("No database with %s %q found in Metabase", attribute, value),
 		})
-		return diags
-	}
-
-	if err := d.Set("description", database.Description); err != nil {
+		return 0, diags
+	case 1:
+		return id, diags
+	default:
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Unable to assign database.Description to d.description in dataSourceBaseRead()",
-			Detail:   "Unable to assign database.Description to d.description in dataSourceBaseRead()",
+			Summary:  "Multiple databases found",
+			Detail: fmt.Sprintf("%d databases with %s %q found in Metabase (IDs %s), use id to select one of them",
+				len(ids), attribute, value, strings.Join(ids, ", ")),
 		})
-		return diags
+		return 0, diags
 	}
+}
 
-	d.SetId(strconv.Itoa(database.Id))
+func flattenSchedules(schedules Schedules) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"cache_field_values": []interface{}{flattenSchedule(schedules.CacheFieldValues)},
+			"metadata_sync":      []interface{}{flattenSchedule(schedules.MetadataSync)},
+		},
+	}
+}
 
-	return diags
+func flattenSchedule(schedule CacheFieldValues) map[string]interface{} {
+	return map[string]interface{}{
+		"schedule_minute": schedule.ScheduleMinute,
+		"schedule_day":    schedule.ScheduleDay,
+		"schedule_frame":  schedule.ScheduleFrame,
+		"schedule_hour":   schedule.ScheduleHour,
+		"schedule_type":   schedule.ScheduleType,
+	}
+}
+
+func flattenTablesWithFields(tables []Table) []interface{} {
+	ois := flattenTables(tables)
+
+	for i, table := range tables {
+		ois[i].(map[string]interface{})["fields"] = flattenFields(table.Fields)
+	}
+
+	return ois
+}
+
+func flattenFields(fields []Field) []interface{} {
+	ois := make([]interface{}, len(fields), len(fields))
+
+	for i, field := range fields {
+		ois[i] = map[string]interface{}{
+			"id":                 field.Id,
+			"name":               field.Name,
+			"display_name":       field.DisplayName,
+			"description":        field.Description,
+			"base_type":          field.BaseType,
+			"semantic_type":      field.SemanticType,
+			"database_type":      field.DatabaseType,
+			"visibility_type":    field.VisibilityType,
+			"fk_target_field_id": field.FkTargetFieldId,
+		}
+	}
+
+	return ois
 }
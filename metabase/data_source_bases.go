@@ -2,14 +2,16 @@ package metabase
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceBases() *schema.Resource {
@@ -18,116 +20,56 @@ func dataSourceBases() *schema.Resource {
 			"Example response can be found in [schema_data_databases.txt](../schema_data_databases.txt)",
 		ReadContext: dataSourceBasesRead,
 		Schema: map[string]*schema.Schema{
+			"engine": &schema.Schema{
+				Description: "Only return databases of this engine, e.g. `postgres`",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"name_regex": &schema.Schema{
+				Description:  "Only return databases which name matches this regular expression",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"include_sample": &schema.Schema{
+				Description: "Whether to return the sample database shipped with Metabase",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"include_tables": &schema.Schema{
+				Description: "Whether to return the tables of every database",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"saved": &schema.Schema{
+				Description: "Whether to return the virtual \"Saved Questions\" database",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"ids": &schema.Schema{
+				Description: "IDs of the returned databases",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"names_to_ids": &schema.Schema{
+				Description: "Map of names of the returned databases to their IDs. When several databases share a name, the one with the lowest ID is kept",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
 			"databases": &schema.Schema{
 				Type:     schema.TypeList,
 				Computed: true,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"description": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"features": &schema.Schema{
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"cache_field_values_schedule": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"timezone": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"auto_run_queries": &schema.Schema{
-							Description: "Example: \"\"",
-							Type:        schema.TypeBool,
-							Computed:    true,
-						},
-						"metadata_sync_schedule": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"name": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"caveats": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"is_full_sync": &schema.Schema{
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"updated_at": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"native_permissions": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"details_host": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"details_port": &schema.Schema{
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"details_db": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"details_user": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"details_password": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"details_ssl": &schema.Schema{
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"is_sample": &schema.Schema{
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"id": &schema.Schema{
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"is_on_demand": &schema.Schema{
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"options": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"engine": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"refingerprint": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"created_at": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"points_of_interest": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-					},
+					Schema: databaseSchema(),
 				},
 			},
 		},
@@ -137,87 +79,91 @@ func dataSourceBases() *schema.Resource {
 func dataSourceBasesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
 
-	client := c.HTTPClient
-
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/database", c.HostURL), nil)
-	if err != nil {
-		return diag.FromErr(err)
+	query := url.Values{}
+	if d.Get("include_tables").(bool) {
+		query.Set("include", "tables")
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Metabase-Session", c.Token)
-	// disable gzip
-	req.Header.Set("Accept-Encoding", "identity")
-
-	r, err := client.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
+	if d.Get("saved").(bool) {
+		query.Set("saved", "true")
 	}
-	defer r.Body.Close()
 
 	var databases []Database
-
-	//body, err := ioutil.ReadAll(r.Body)
-	//err = json.Unmarshal(body, &databases)
-	err = json.NewDecoder(r.Body).Decode(&databases)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "Unable to decode",
-			Detail:   fmt.Sprintf("Unable to decode JSON: %s", err.Error()),
-		})
-		return diags
+	if err := c.getList(ctx, fmt.Sprintf("/api/database?%s", query.Encode()), &databases); err != nil {
+		return apiErrorDiags(err, "Databases not found")
 	}
 
+	databases = filterDatabases(databases, d)
+
 	flattenned_databases := flattenDatabases(databases)
 
 	if err := d.Set("databases", flattenned_databases); err != nil {
 		return diag.FromErr(err)
 	}
 
+	ids := make([]int, 0, len(databases))
+	namesToIds := make(map[string]int, len(databases))
+	for _, database := range databases {
+		ids = append(ids, database.Id)
+		// the oldest database wins when several share a name
+		if id, ok := namesToIds[database.Name]; ok {
+			tflog.Warn(ctx, "Several databases share a name, names_to_ids keeps the lowest ID", map[string]interface{}{
+				"name": database.Name,
+				"ids":  []int{id, database.Id},
+			})
+			if id < database.Id {
+				continue
+			}
+		}
+		namesToIds[database.Name] = database.Id
+	}
+
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("names_to_ids", namesToIds); err != nil {
+		return diag.FromErr(err)
+	}
+
 	// always run
 	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
 
 	return diags
 }
 
+// filterDatabases applies filters which Metabase API does not support itself
+func filterDatabases(databases []Database, d *schema.ResourceData) []Database {
+	engine := d.Get("engine").(string)
+	includeSample := d.Get("include_sample").(bool)
+	// validated by StringIsValidRegExp
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	filtered := make([]Database, 0, len(databases))
+	for _, database := range databases {
+		if engine != "" && database.Engine != engine {
+			continue
+		}
+		if !includeSample && database.IsSample {
+			continue
+		}
+		if !nameRegex.MatchString(database.Name) {
+			continue
+		}
+		filtered = append(filtered, database)
+	}
+
+	return filtered
+}
+
 func flattenDatabases(databases []Database) []interface{} {
 	if databases != nil {
 		ois := make([]interface{}, len(databases), len(databases))
 
 		for i, database := range databases {
-			oi := make(map[string]interface{})
-
-			oi["description"] = database.Description
-			oi["features"] = database.Features
-			oi["cache_field_values_schedule"] = database.CacheFieldValuesSchedule
-			oi["timezone"] = database.Timezone
-			oi["auto_run_queries"] = database.AutoRunQueries
-			oi["metadata_sync_schedule"] = database.MetadataSyncSchedule
-			oi["name"] = database.Name
-			oi["caveats"] = database.Caveats
-			oi["is_full_sync"] = database.IsFullSync
-			oi["updated_at"] = database.UpdatedAt
-			oi["native_permissions"] = database.NativePermissions
-			oi["details_host"] = database.Details.Host
-			oi["details_port"] = database.Details.Port
-			oi["details_db"] = database.Details.Db
-			oi["details_user"] = database.Details.User
-			oi["details_password"] = database.Details.Password
-			oi["details_ssl"] = database.Details.Ssl
-			oi["is_sample"] = database.IsSample
-			oi["id"] = database.Id
-			oi["is_on_demand"] = database.IsOnDemand
-			oi["options"] = database.Options
-			oi["engine"] = database.Engine
-			oi["refingerprint"] = database.Refingerprint
-			oi["created_at"] = database.CreatedAt
-			oi["points_of_interest"] = database.PointsOfInterest
-
-			ois[i] = oi
+			ois[i] = flattenDatabase(database)
 		}
 
 		return ois
@@ -225,3 +171,210 @@ func flattenDatabases(databases []Database) []interface{} {
 
 	return make([]interface{}, 0)
 }
+
+func flattenDatabase(database Database) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	oi["description"] = database.Description
+	oi["features"] = database.Features
+	oi["cache_field_values_schedule"] = database.CacheFieldValuesSchedule
+	oi["timezone"] = database.Timezone
+	oi["auto_run_queries"] = database.AutoRunQueries
+	oi["metadata_sync_schedule"] = database.MetadataSyncSchedule
+	oi["name"] = database.Name
+	oi["caveats"] = database.Caveats
+	oi["is_full_sync"] = database.IsFullSync
+	oi["updated_at"] = database.UpdatedAt
+	oi["native_permissions"] = database.NativePermissions
+	oi["details_host"] = database.Details.Host
+	oi["details_port"] = database.Details.Port
+	oi["details_db"] = database.Details.Db
+	oi["details_user"] = database.Details.User
+	oi["details_password"] = database.Details.Password
+	oi["details_ssl"] = database.Details.Ssl
+	oi["is_sample"] = database.IsSample
+	oi["id"] = database.Id
+	oi["is_on_demand"] = database.IsOnDemand
+	oi["options"] = database.Options
+	oi["engine"] = database.Engine
+	oi["refingerprint"] = database.Refingerprint
+	oi["created_at"] = database.CreatedAt
+	oi["points_of_interest"] = database.PointsOfInterest
+	oi["tables"] = flattenTables(database.Tables)
+
+	return oi
+}
+
+func databaseSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"description": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"features": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"cache_field_values_schedule": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"timezone": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"auto_run_queries": &schema.Schema{
+			Description: "Example: \"\"",
+			Type:        schema.TypeBool,
+			Computed:    true,
+		},
+		"metadata_sync_schedule": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"caveats": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"is_full_sync": &schema.Schema{
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"updated_at": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"native_permissions": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"details_host": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"details_port": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"details_db": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"details_user": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"details_password": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"details_ssl": &schema.Schema{
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"is_sample": &schema.Schema{
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"is_on_demand": &schema.Schema{
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"options": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"engine": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"refingerprint": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"created_at": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"points_of_interest": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"tables": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: tableSchema(),
+			},
+		},
+	}
+}
+
+func tableSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"schema": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"display_name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"entity_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"visibility_type": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"active": &schema.Schema{
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+	}
+}
+
+func flattenTables(tables []Table) []interface{} {
+	ois := make([]interface{}, len(tables), len(tables))
+
+	for i, table := range tables {
+		oi := make(map[string]interface{})
+
+		oi["id"] = table.Id
+		oi["name"] = table.Name
+		oi["schema"] = table.Schema
+		oi["display_name"] = table.DisplayName
+		oi["description"] = table.Description
+		oi["entity_type"] = table.EntityType
+		oi["visibility_type"] = table.VisibilityType
+		oi["active"] = table.Active
+
+		ois[i] = oi
+	}
+
+	return ois
+}
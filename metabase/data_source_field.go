@@ -0,0 +1,121 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceField() *schema.Resource {
+	s := fieldSchema()
+
+	lookupKeys := []string{"id", "name"}
+	nameKeys := []string{"database_id", "table", "name"}
+
+	s["id"] = &schema.Schema{
+		Description:   "Field ID",
+		Type:          schema.TypeInt,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"database_id", "schema", "table", "name"},
+		ExactlyOneOf:  lookupKeys,
+	}
+	s["database_id"] = &schema.Schema{
+		Description:  "ID of the database of a field, required to look up a field by name",
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		RequiredWith: nameKeys,
+	}
+	s["schema"] = &schema.Schema{
+		Description: "Schema of the table of a field. Can be omitted when the table name is unique in the database",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+	}
+	s["table"] = &schema.Schema{
+		Description:  "Name of the table of a field, required to look up a field by name",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		RequiredWith: nameKeys,
+	}
+	s["name"] = &schema.Schema{
+		Description:  "Name of a field. Can be used with `database_id` and `table` instead of `id` to look up a field",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		RequiredWith: nameKeys,
+		ExactlyOneOf: lookupKeys,
+	}
+	s["table_id"] = &schema.Schema{
+		Description: "ID of the table of a field",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	return &schema.Resource{
+		Description: "`metabase_field` data source can be used to retrieve a field synced by Metabase, " +
+			"including its base and semantic types.\n\n" +
+			"A field can be looked up either by `id`, or by `database_id`, `table`, `name` and optionally `schema`.",
+		ReadContext: dataSourceFieldRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceFieldRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	var field *Field
+	table := &Table{}
+	if id := d.Get("id").(int); id != 0 {
+		var err error
+		field, err = readField(ctx, c, strconv.Itoa(id))
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Field %d not found", id))
+		}
+		err = c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/table/%d", field.TableId), nil, table)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Table %d not found", field.TableId))
+		}
+	} else {
+		var lookupDiags diag.Diagnostics
+		table, lookupDiags = findTable(ctx, c, d.Get("database_id").(int), d.Get("schema").(string), d.Get("table").(string))
+		if lookupDiags.HasError() {
+			return lookupDiags
+		}
+		field, lookupDiags = findField(ctx, c, table, d.Get("name").(string))
+		if lookupDiags.HasError() {
+			return lookupDiags
+		}
+	}
+
+	flattened := flattenFields([]Field{*field})[0].(map[string]interface{})
+	delete(flattened, "id")
+	flattened["table_id"] = field.TableId
+	flattened["database_id"] = table.DbId
+	flattened["schema"] = table.Schema
+	flattened["table"] = table.Name
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign field attribute to d.%s in dataSourceFieldRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	d.SetId(strconv.Itoa(field.Id))
+
+	return diags
+}
@@ -0,0 +1,67 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePermissionsGroup() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_permissions_group` data source can be used to resolve the ID of a permissions group by its name, " +
+			"including the built-in \"All Users\" and \"Administrators\" groups.\n\n",
+		ReadContext: dataSourcePermissionsGroupRead,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Description: "Name of a group",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"member_count": &schema.Schema{
+				Description: "Number of users in a group",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourcePermissionsGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	name := d.Get("name").(string)
+
+	group, err := findPermissionsGroup(ctx, c, func(g PermissionsGroup) bool {
+		return g.Name == name
+	})
+	if err != nil {
+		return apiErrorDiags(err, "Permissions groups not found")
+	}
+	if group == nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Permissions group not found",
+			Detail:   fmt.Sprintf("No permissions group with name %q found in Metabase", name),
+		})
+		return diags
+	}
+
+	if err := d.Set("member_count", group.MemberCount); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign group.MemberCount to d.member_count in dataSourcePermissionsGroupRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+
+	return diags
+}
@@ -0,0 +1,63 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceSchemas() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_schemas` data source can be used to retrieve the schemas of a database synced by Metabase.",
+		ReadContext: dataSourceSchemasRead,
+		Schema: map[string]*schema.Schema{
+			"database_id": &schema.Schema{
+				Description: "ID of a database",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+			"schemas": &schema.Schema{
+				Description: "Names of the schemas of a database, empty for databases without schemas",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSchemasRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	databaseId := d.Get("database_id").(int)
+
+	var schemas []string
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/database/%d/schemas", databaseId), nil, &schemas)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Database %d not found", databaseId))
+	}
+	sort.Strings(schemas)
+
+	if err := d.Set("schemas", schemas); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign schemas to d.schemas in dataSourceSchemasRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	d.SetId(strconv.Itoa(databaseId))
+
+	return diags
+}
@@ -0,0 +1,109 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTable() *schema.Resource {
+	s := tableWithFieldsSchema()
+
+	lookupKeys := []string{"id", "name"}
+
+	s["id"] = &schema.Schema{
+		Description:   "Table ID",
+		Type:          schema.TypeInt,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name", "schema"},
+		ExactlyOneOf:  lookupKeys,
+	}
+	s["database_id"] = &schema.Schema{
+		Description:  "ID of the database of a table, required to look up a table by name",
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		RequiredWith: []string{"name"},
+	}
+	s["schema"] = &schema.Schema{
+		Description: "Schema of a table. Can be omitted when the table name is unique in the database",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+	}
+	s["name"] = &schema.Schema{
+		Description:  "Name of a table. Can be used with `database_id` instead of `id` to look up a table",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		RequiredWith: []string{"database_id"},
+		ExactlyOneOf: lookupKeys,
+	}
+	s["fields_to_ids"] = &schema.Schema{
+		Description: "Map of names of the fields of a table to their IDs",
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeInt,
+		},
+	}
+
+	return &schema.Resource{
+		Description: "`metabase_table` data source can be used to retrieve a table synced by Metabase with its fields.\n\n" +
+			"A table can be looked up either by `id`, or by `database_id`, `name` and optionally `schema`.",
+		ReadContext: dataSourceTableRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceTableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	tableId := d.Get("id").(int)
+	if tableId == 0 {
+		table, lookupDiags := findTable(ctx, c, d.Get("database_id").(int), d.Get("schema").(string), d.Get("name").(string))
+		if lookupDiags.HasError() {
+			return lookupDiags
+		}
+		tableId = table.Id
+	}
+
+	table := &Table{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/table/%d/query_metadata", tableId), nil, table)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Table %d not found", tableId))
+	}
+
+	flattened := flattenTablesWithFields([]Table{*table})[0].(map[string]interface{})
+	delete(flattened, "id")
+	flattened["database_id"] = table.DbId
+
+	fieldsToIds := make(map[string]int, len(table.Fields))
+	for _, field := range table.Fields {
+		fieldsToIds[field.Name] = field.Id
+	}
+	flattened["fields_to_ids"] = fieldsToIds
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign table attribute to d.%s in dataSourceTableRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	d.SetId(strconv.Itoa(table.Id))
+
+	return diags
+}
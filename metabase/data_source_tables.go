@@ -0,0 +1,117 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTables() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_tables` data source can be used to retrieve the tables of a database with their fields.",
+		ReadContext: dataSourceTablesRead,
+		Schema: map[string]*schema.Schema{
+			"database_id": &schema.Schema{
+				Description: "ID of a database",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+			"schema": &schema.Schema{
+				Description: "Only return tables of this schema",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ids": &schema.Schema{
+				Description: "IDs of the returned tables",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"names_to_ids": &schema.Schema{
+				Description: "Map of names of the returned tables to their IDs, " +
+					"filter by `schema` when table names repeat across schemas. " +
+					"When several tables share a name, the one with the lowest ID is kept",
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"tables": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: tableWithFieldsSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTablesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	databaseId := d.Get("database_id").(int)
+	schemaName := d.Get("schema").(string)
+
+	database, metadataDiags := readDatabaseMetadata(ctx, c, strconv.Itoa(databaseId))
+	if metadataDiags.HasError() {
+		return metadataDiags
+	}
+
+	tables := make([]Table, 0, len(database.Tables))
+	for _, table := range database.Tables {
+		if schemaName != "" && table.Schema != schemaName {
+			continue
+		}
+		tables = append(tables, table)
+	}
+
+	ids := make([]int, 0, len(tables))
+	namesToIds := make(map[string]int, len(tables))
+	for _, table := range tables {
+		ids = append(ids, table.Id)
+
+		// the oldest table wins when several share a name
+		if id, ok := namesToIds[table.Name]; ok {
+			tflog.Warn(ctx, "Several tables share a name, names_to_ids keeps the lowest ID", map[string]interface{}{
+				"name": table.Name,
+				"ids":  []int{id, table.Id},
+			})
+			if id < table.Id {
+				continue
+			}
+		}
+		namesToIds[table.Name] = table.Id
+	}
+
+	flattened := map[string]interface{}{
+		"tables":       flattenTablesWithFields(tables),
+		"ids":          ids,
+		"names_to_ids": namesToIds,
+	}
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign tables attribute to d.%s in dataSourceTablesRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s", databaseId, schemaName))
+
+	return diags
+}
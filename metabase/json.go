@@ -0,0 +1,132 @@
+package metabase
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serverAddedJSONKeys are added by Metabase to stored JSON and are never
+// part of a configuration
+var serverAddedJSONKeys = map[string]bool{
+	"lib/uuid": true,
+}
+
+// serverDefaultJSONValues are added by Metabase to stored JSON when missing,
+// they are removed only when they still hold their default value
+var serverDefaultJSONValues = map[string]string{
+	// native queries without variables
+	"template-tags": "{}",
+}
+
+// numericJSONKeys hold IDs which are compared as numbers, so string IDs
+// of other resources can be used in jsonencode() as is. They are mapped to
+// the keys of the objects they belong to, "" being the top level object.
+var numericJSONKeys = map[string]map[string]bool{
+	"database":     {"": true},
+	"source-table": {"": true, "query": true, "source-query": true, "joins": true},
+}
+
+// normalizeJSON canonicalizes JSON blobs like queries, visualization settings
+// and parameters, which Metabase re-serializes with a different key order and
+// added defaults: keys are sorted, keys added by Metabase are removed and
+// numeric IDs are unified. Nulls and empty values are kept, they clear settings.
+func normalizeJSON(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(normalizeJSONValue(v, ""))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// normalizeJSONValue normalizes a value held by the object key parent
+func normalizeJSONValue(v interface{}, parent string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			if serverAddedJSONKeys[k] || isServerDefaultJSONValue(k, item) {
+				continue
+			}
+			if s, ok := item.(string); ok && numericJSONKeys[k][parent] {
+				if _, err := strconv.Atoi(s); err == nil {
+					item = json.Number(s)
+				}
+			}
+			m[k] = normalizeJSONValue(item, k)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(value))
+		for i, item := range value {
+			a[i] = normalizeJSONValue(item, parent)
+		}
+		return a
+	default:
+		return value
+	}
+}
+
+func isServerDefaultJSONValue(k string, v interface{}) bool {
+	def, ok := serverDefaultJSONValues[k]
+	if !ok {
+		return false
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	return string(b) == def
+}
+
+// jsonStateFunc stores JSON attributes in their canonical form
+func jsonStateFunc(v interface{}) string {
+	normalized, err := normalizeJSON(v.(string))
+	if err != nil {
+		// invalid JSON is reported by validation
+		return v.(string)
+	}
+	return normalized
+}
+
+// suppressEquivalentJSON suppresses diffs between JSON attributes
+// which are equal once normalized
+func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
+	normalizedOld, err := normalizeJSON(old)
+	if err != nil {
+		return false
+	}
+
+	normalizedNew, err := normalizeJSON(new)
+	if err != nil {
+		return false
+	}
+
+	return normalizedOld == normalizedNew
+}
+
+// flattenJSON returns the canonical form of a JSON blob received from Metabase,
+// empty is used when the blob is missing
+func flattenJSON(raw json.RawMessage, empty string) (string, error) {
+	if len(bytes.TrimSpace(raw)) == 0 || string(bytes.TrimSpace(raw)) == "null" {
+		return empty, nil
+	}
+
+	return normalizeJSON(string(raw))
+}
@@ -0,0 +1,103 @@
+package metabase
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty string", ``, ``},
+		{"sorts keys", `{"b": 1, "a": {"d": 2, "c": 3}}`, `{"a":{"c":3,"d":2},"b":1}`},
+		{"keeps top level empty object", `{}`, `{}`},
+		{"keeps top level empty array", `[]`, `[]`},
+		{"keeps top level null", `null`, `null`},
+		{"keeps nulls", `{"graph.goal_value": null}`, `{"graph.goal_value":null}`},
+		{"keeps empty arrays", `{"graph.dimensions": []}`, `{"graph.dimensions":[]}`},
+		{"keeps empty objects", `{"column_settings": {}}`, `{"column_settings":{}}`},
+		{"keeps nulls in arrays", `[null, 1]`, `[null,1]`},
+		{"keeps decimals", `{"graph.goal_value": 3.0}`, `{"graph.goal_value":3.0}`},
+		{"keeps large numbers", `{"n": 12345678901234567890}`, `{"n":12345678901234567890}`},
+		{"strips lib/uuid", `{"query": {"filter": ["=", ["field", 1, {"lib/uuid": "x"}], 2]}}`, `{"query":{"filter":["=",["field",1,{}],2]}}`},
+		{"strips default template tags", `{"native": {"query": "select 1", "template-tags": {}}}`, `{"native":{"query":"select 1"}}`},
+		{"keeps template tags", `{"native": {"template-tags": {"x": {"type": "text"}}}}`, `{"native":{"template-tags":{"x":{"type":"text"}}}}`},
+		{"converts top level database", `{"database": "3", "type": "query"}`, `{"database":3,"type":"query"}`},
+		{"converts source table of a query", `{"query": {"source-table": "7"}}`, `{"query":{"source-table":7}}`},
+		{"converts top level source table", `{"source-table": "7"}`, `{"source-table":7}`},
+		{"converts source table of a source query", `{"query": {"source-query": {"source-table": "7"}}}`, `{"query":{"source-query":{"source-table":7}}}`},
+		{"converts source table of joins", `{"query": {"joins": [{"source-table": "8"}]}}`, `{"query":{"joins":[{"source-table":8}]}}`},
+		{"keeps card source tables", `{"query": {"source-table": "card__12"}}`, `{"query":{"source-table":"card__12"}}`},
+		{"keeps nested database strings", `{"click_behavior": {"database": "3"}}`, `{"click_behavior":{"database":"3"}}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := normalizeJSON(c.in)
+			if err != nil {
+				t.Fatalf("normalizeJSON(%s) returned error: %s", c.in, err)
+			}
+			if got != c.want {
+				t.Errorf("normalizeJSON(%s) = %s, want %s", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeJSONInvalid(t *testing.T) {
+	if _, err := normalizeJSON(`{"a":`); err == nil {
+		t.Error("normalizeJSON of invalid JSON returned no error")
+	}
+}
+
+func TestSuppressEquivalentJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+		want     bool
+	}{
+		{"key order", `{"a": 1, "b": 2}`, `{"b": 2, "a": 1}`, true},
+		{"string IDs", `{"database": 1}`, `{"database": "1"}`, true},
+		{"server added keys", `{"query": {"lib/uuid": "x", "limit": 1}}`, `{"query": {"limit": 1}}`, true},
+		{"cleared value", `{"graph.goal_value": 5}`, `{"graph.goal_value": null}`, false},
+		{"cleared array", `{"graph.metrics": ["count"]}`, `{"graph.metrics": []}`, false},
+		{"removed key", `{"graph.goal_value": null}`, `{}`, false},
+		{"invalid JSON", `{}`, `{`, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := suppressEquivalentJSON("", c.old, c.new, nil); got != c.want {
+				t.Errorf("suppressEquivalentJSON(%s, %s) = %t, want %t", c.old, c.new, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFlattenJSON(t *testing.T) {
+	cases := []struct {
+		name  string
+		raw   json.RawMessage
+		empty string
+		want  string
+	}{
+		{"missing", nil, "{}", "{}"},
+		{"null", json.RawMessage(`null`), "[]", "[]"},
+		{"value", json.RawMessage(`{"b": [], "a": null}`), "{}", `{"a":null,"b":[]}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := flattenJSON(c.raw, c.empty)
+			if err != nil {
+				t.Fatalf("flattenJSON(%s) returned error: %s", c.raw, err)
+			}
+			if got != c.want {
+				t.Errorf("flattenJSON(%s) = %s, want %s", c.raw, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package metabase
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const redacted = "***"
+
+// sensitiveHeaders are never written to the log as is
+var sensitiveHeaders = []string{
+	"X-Metabase-Session",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// sensitiveKeys are JSON keys which values are replaced in logged bodies,
+// wherever they appear in a request or a response
+var sensitiveKeys = map[string]bool{
+	"password":                      true,
+	"pass":                          true,
+	"service-account-json":          true,
+	"tunnel-pass":                   true,
+	"tunnel-private-key":            true,
+	"tunnel-private-key-passphrase": true,
+	"ssl-key-value":                 true,
+	"ssl-client-key-value":          true,
+	"email-smtp-password":           true,
+	"slack-app-token":               true,
+	"slack-token":                   true,
+	"ldap-password":                 true,
+	"embedding-secret-key":          true,
+	"premium-embedding-token":       true,
+	"openai-api-key":                true,
+}
+
+// loggingTransport logs every call made through the Client with tflog,
+// so the calls become visible with TF_LOG=DEBUG
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}
+
+	requestFields := map[string]interface{}{
+		"headers": redactHeaders(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			body.Close()
+			requestFields["body"] = redactBody(req, b)
+		}
+	}
+	tflog.Debug(ctx, "Sending request to Metabase", mergeFields(fields, requestFields))
+
+	start := time.Now()
+	res, err := t.transport.RoundTrip(req)
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "Request to Metabase failed", fields)
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	tflog.Debug(ctx, "Received response from Metabase", mergeFields(fields, map[string]interface{}{
+		"status":  res.StatusCode,
+		"headers": redactHeaders(res.Header),
+		"body":    redactBody(req, body),
+	}))
+
+	return res, nil
+}
+
+func mergeFields(a, b map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
+
+// redactHeaders keeps every value of repeated headers, e.g. several Set-Cookie lines
+func redactHeaders(h http.Header) map[string][]string {
+	headers := make(map[string][]string, len(h))
+	for k, values := range h {
+		headers[k] = append([]string(nil), values...)
+	}
+	for _, k := range sensitiveHeaders {
+		values := headers[http.CanonicalHeaderKey(k)]
+		for i := range values {
+			values[i] = redacted
+		}
+	}
+	return headers
+}
+
+// redactBody masks sensitive values of a JSON body. Bodies of /api/session
+// calls carry credentials and the session token and are masked completely,
+// as are bodies of /api/setting/:key calls for sensitive keys, which only
+// name the setting in the path. Non-JSON bodies are returned unchanged.
+func redactBody(req *http.Request, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	path := strings.TrimRight(req.URL.Path, "/")
+	if strings.HasSuffix(path, "/api/session") {
+		return redacted
+	}
+	if i := strings.LastIndex(path, "/api/setting/"); i >= 0 && sensitiveKeys[strings.ToLower(path[i+len("/api/setting/"):])] {
+		return redacted
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			if sensitiveKeys[strings.ToLower(k)] && item != nil {
+				value[k] = redacted
+				continue
+			}
+			value[k] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+	return v
+}
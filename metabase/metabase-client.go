@@ -1,12 +1,21 @@
 package metabase
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Client -
@@ -14,6 +23,14 @@ type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
 	Token      string
+
+	// graphLocks holds a *sync.Mutex per permissions graph path
+	graphLocks sync.Map
+
+	// versionLock guards versionMinor, which is looked up once
+	versionLock  sync.Mutex
+	versionKnown bool
+	versionMinor int
 }
 
 // AuthStruct -
@@ -28,9 +45,12 @@ type AuthResponse struct {
 }
 
 // NewClient -
-func NewClient(url, username, password *string) (*Client, error) {
+func NewClient(ctx context.Context, url, username, password *string) (*Client, error) {
 	c := Client{
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		HTTPClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &loggingTransport{transport: http.DefaultTransport},
+		},
 	}
 
 	if url != nil {
@@ -48,12 +68,15 @@ func NewClient(url, username, password *string) (*Client, error) {
 		}
 
 		// authenticate
-		req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/session", c.HostURL), strings.NewReader(string(rb)))
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/session", c.HostURL), strings.NewReader(string(rb)))
 		if err != nil {
 			return nil, err
 		}
 
 		body, err := c.doRequest(req)
+		if err != nil {
+			return nil, err
+		}
 
 		// parse response body
 		ar := AuthResponse{}
@@ -68,23 +91,224 @@ func NewClient(url, username, password *string) (*Client, error) {
 	return &c, nil
 }
 
-func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	req.Header.Set("Content-Type", "application/json")
+// APIError is returned when Metabase responds with an unsuccessful status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
 
-	res, err := c.HTTPClient.Do(req)
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// versionTagRegexp matches version tags like v0.48.1 or v1.48.1 (Enterprise)
+var versionTagRegexp = regexp.MustCompile(`^v?\d+\.(\d+)`)
+
+// minorVersion returns the minor version of Metabase, e.g. 48 for v0.48.1, or 0
+// when the version tag cannot be parsed, e.g. for development builds
+func (c *Client) minorVersion(ctx context.Context) (int, error) {
+	c.versionLock.Lock()
+	defer c.versionLock.Unlock()
+
+	if c.versionKnown {
+		return c.versionMinor, nil
+	}
+
+	var properties struct {
+		Version struct {
+			Tag string `json:"tag"`
+		} `json:"version"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/api/session/properties", nil, &properties); err != nil {
+		return 0, err
+	}
+
+	if match := versionTagRegexp.FindStringSubmatch(properties.Version.Tag); match != nil {
+		c.versionMinor, _ = strconv.Atoi(match[1])
+	}
+	c.versionKnown = true
+
+	return c.versionMinor, nil
+}
+
+// requireVersion returns an error naming feature when Metabase is older than
+// the given minor version, unknown versions are assumed to be recent enough
+func (c *Client) requireVersion(ctx context.Context, minor int, feature string) error {
+	current, err := c.minorVersion(ctx)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if current != 0 && current < minor {
+		return fmt.Errorf("%s requires Metabase 0.%d or later, the server runs 0.%d", feature, minor, current)
+	}
+
+	return nil
+}
+
+// isNotFound reports whether err is an *APIError with 404 status
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isConflict reports whether err is an *APIError with 409 status, which Metabase
+// returns when a revisioned graph was changed since it has been read
+func isConflict(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusConflict
+}
+
+// newAPIError extracts the message from an error body, which Metabase
+// sends either as plain text or as JSON with "message" or "errors" keys
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Message:    strings.TrimSpace(string(body)),
 	}
-	defer res.Body.Close()
 
+	var v struct {
+		Message string                 `json:"message"`
+		Errors  map[string]interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return e
+	}
+
+	switch {
+	case v.Message != "":
+		e.Message = v.Message
+	case len(v.Errors) > 0:
+		var msgs []string
+		for k, msg := range v.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s: %v", k, msg))
+		}
+		sort.Strings(msgs)
+		e.Message = strings.Join(msgs, "; ")
+	}
+
+	return e
+}
+
+// checkResponse reads the body of a response and returns an *APIError
+// unless the response is successful
+func checkResponse(res *http.Response) ([]byte, error) {
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, newAPIError(res.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// doJSON sends in as a JSON body, when it is not nil, to the path of Metabase API
+// and decodes a successful response into out, when it is not nil
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	var rb io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		rb = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", c.HostURL, path), rb)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Metabase-Session", c.Token)
+	// disable gzip
+	req.Header.Set("Accept-Encoding", "identity")
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// getList decodes a list returned by Metabase API into out. Newer Metabase
+// versions wrap paginated lists into {"data": [...]}, older return plain arrays.
+func (c *Client) getList(ctx context.Context, path string, out interface{}) error {
+	var body json.RawMessage
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &body); err != nil {
+		return err
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var page struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(trimmed, &page); err != nil {
+			return err
+		}
+		body = page.Data
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// graphUpdateAttempts limits how many times a permissions graph update is retried
+// when the graph is changed by someone else in between
+const graphUpdateAttempts = 5
+
+// updateGraph runs read-modify-write cycles of the revisioned permissions graph
+// at path, one at a time per graph. modify receives the current graph and returns
+// the partial graph to submit, including the revision it was built from. The cycle
+// is repeated with a fresh graph while Metabase rejects the revision as outdated.
+func (c *Client) updateGraph(ctx context.Context, path string, modify func(current []byte) (interface{}, error)) error {
+	lock, _ := c.graphLocks.LoadOrStore(path, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	for attempt := 1; ; attempt++ {
+		var current json.RawMessage
+		if err := c.doJSON(ctx, http.MethodGet, path, nil, &current); err != nil {
+			return err
+		}
+
+		update, err := modify(current)
+		if err != nil {
+			return err
+		}
+
+		err = c.doJSON(ctx, http.MethodPut, path, update, nil)
+		if !isConflict(err) || attempt == graphUpdateAttempts {
+			return err
+		}
+
+		tflog.Debug(ctx, "Permissions graph revision is outdated, retrying", map[string]interface{}{
+			"path":    path,
+			"attempt": attempt,
+		})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		}
+	}
+}
+
+func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer res.Body.Close()
 
-	return body, err
+	return checkResponse(res)
 }
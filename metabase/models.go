@@ -1,5 +1,7 @@
 package metabase
 
+import "encoding/json"
+
 type Details struct {
 	Host     string `json:"host,omitempty"`
 	Port     int    `json:"port,omitempty"`
@@ -31,29 +33,302 @@ type Schedules struct {
 
 type DatabaseRead struct {
 	*Database
-	Details   Details `json:"details"`
-	Schedules Schedules  `json:"schedules"`
+	Details   Details   `json:"details"`
+	Schedules Schedules `json:"schedules"`
 }
 
 type Database struct {
-	Description              string     `json:"description"`
-	Features                 []string   `json:"features"`
-	CacheFieldValuesSchedule string     `json:"cache_field_values_schedule"`
-	Timezone                 string     `json:"timezone"`
-	AutoRunQueries           bool       `json:"auto_run_queries"`
-	MetadataSyncSchedule     string     `json:"metadata_sync_schedule"`
-	Name                     string     `json:"name"`
-	Caveats                  string     `json:"caveats"`
-	IsFullSync               bool       `json:"is_full_sync"`
-	UpdatedAt                string     `json:"updated_at"`
-	NativePermissions        string     `json:"native_permissions"`
-	Details                  Details `json:"details"`
-	IsSample                 bool       `json:"is_sample"`
-	Id                       int        `json:"id"`
-	IsOnDemand               bool       `json:"is_on_demand"`
-	Options                  string     `json:"options"`
-	Engine                   string     `json:"engine"`
-	Refingerprint            string     `json:"refingerprint"`
-	CreatedAt                string     `json:"created_at"`
-	PointsOfInterest         string     `json:"points_of_interest"`
+	Description              string   `json:"description"`
+	Features                 []string `json:"features"`
+	CacheFieldValuesSchedule string   `json:"cache_field_values_schedule"`
+	Timezone                 string   `json:"timezone"`
+	AutoRunQueries           bool     `json:"auto_run_queries"`
+	MetadataSyncSchedule     string   `json:"metadata_sync_schedule"`
+	Name                     string   `json:"name"`
+	Caveats                  string   `json:"caveats"`
+	IsFullSync               bool     `json:"is_full_sync"`
+	UpdatedAt                string   `json:"updated_at"`
+	NativePermissions        string   `json:"native_permissions"`
+	Details                  Details  `json:"details"`
+	IsSample                 bool     `json:"is_sample"`
+	Id                       int      `json:"id"`
+	IsOnDemand               bool     `json:"is_on_demand"`
+	Options                  string   `json:"options"`
+	Engine                   string   `json:"engine"`
+	Refingerprint            string   `json:"refingerprint"`
+	CreatedAt                string   `json:"created_at"`
+	PointsOfInterest         string   `json:"points_of_interest"`
+	Tables                   []Table  `json:"tables,omitempty"`
+}
+
+type Table struct {
+	Id               int     `json:"id"`
+	DbId             int     `json:"db_id"`
+	Name             string  `json:"name"`
+	Schema           string  `json:"schema"`
+	DisplayName      string  `json:"display_name"`
+	Description      string  `json:"description"`
+	EntityType       string  `json:"entity_type"`
+	VisibilityType   string  `json:"visibility_type"`
+	Caveats          string  `json:"caveats"`
+	PointsOfInterest string  `json:"points_of_interest"`
+	FieldOrder       string  `json:"field_order"`
+	Active           bool    `json:"active"`
+	Fields           []Field `json:"fields,omitempty"`
+}
+
+// TableUpdate holds the metadata of a table editable in Metabase,
+// nil values are reset
+type TableUpdate struct {
+	DisplayName      string  `json:"display_name,omitempty"`
+	Description      *string `json:"description"`
+	EntityType       *string `json:"entity_type,omitempty"`
+	VisibilityType   *string `json:"visibility_type"`
+	Caveats          *string `json:"caveats"`
+	PointsOfInterest *string `json:"points_of_interest"`
+	FieldOrder       string  `json:"field_order,omitempty"`
+}
+
+type Field struct {
+	Id              int             `json:"id"`
+	TableId         int             `json:"table_id"`
+	Name            string          `json:"name"`
+	DisplayName     string          `json:"display_name"`
+	Description     string          `json:"description"`
+	BaseType        string          `json:"base_type"`
+	SemanticType    string          `json:"semantic_type"`
+	DatabaseType    string          `json:"database_type"`
+	VisibilityType  string          `json:"visibility_type"`
+	FkTargetFieldId int             `json:"fk_target_field_id"`
+	HasFieldValues  string          `json:"has_field_values"`
+	Active          bool            `json:"active"`
+	Dimensions      json.RawMessage `json:"dimensions,omitempty"`
+}
+
+// FieldUpdate holds the metadata of a field editable in Metabase
+type FieldUpdate struct {
+	DisplayName     string          `json:"display_name,omitempty"`
+	Description     *string         `json:"description"`
+	SemanticType    json.RawMessage `json:"semantic_type,omitempty"`
+	FkTargetFieldId json.RawMessage `json:"fk_target_field_id,omitempty"`
+	HasFieldValues  string          `json:"has_field_values,omitempty"`
+	VisibilityType  string          `json:"visibility_type,omitempty"`
+}
+
+// FieldDimension remaps the values of a field, either to custom values (internal)
+// or to another field of the table a foreign key points to (external)
+type FieldDimension struct {
+	Type                 string `json:"type"`
+	Name                 string `json:"name"`
+	HumanReadableFieldId *int   `json:"human_readable_field_id"`
+}
+
+// FieldValues holds the values of a field, each one optionally followed by its remapped value
+type FieldValues struct {
+	Values [][]interface{} `json:"values"`
+}
+
+type UserGroupMembership struct {
+	Id int `json:"id"`
+}
+
+type User struct {
+	Id                   int                    `json:"id"`
+	Email                string                 `json:"email"`
+	FirstName            string                 `json:"first_name"`
+	LastName             string                 `json:"last_name"`
+	CommonName           string                 `json:"common_name"`
+	IsSuperuser          bool                   `json:"is_superuser"`
+	IsActive             bool                   `json:"is_active"`
+	Locale               string                 `json:"locale"`
+	LoginAttributes      map[string]interface{} `json:"login_attributes"`
+	GroupIds             []int                  `json:"group_ids"`
+	UserGroupMemberships []UserGroupMembership  `json:"user_group_memberships"`
+	DateJoined           string                 `json:"date_joined"`
+	LastLogin            string                 `json:"last_login"`
+}
+
+type UserCreate struct {
+	Email           string            `json:"email"`
+	FirstName       string            `json:"first_name"`
+	LastName        string            `json:"last_name"`
+	IsSuperuser     bool              `json:"is_superuser"`
+	Locale          *string           `json:"locale"`
+	LoginAttributes map[string]string `json:"login_attributes"`
+	// older Metabase versions only accept group_ids
+	GroupIds             []int                 `json:"group_ids,omitempty"`
+	UserGroupMemberships []UserGroupMembership `json:"user_group_memberships,omitempty"`
+}
+
+// Metabase creates these permissions groups on setup, every user is a member
+// of "All Users" and superusers are members of "Administrators"
+const (
+	allUsersGroupId      = 1
+	administratorGroupId = 2
+)
+
+type PermissionsGroup struct {
+	Id          int                     `json:"id,omitempty"`
+	Name        string                  `json:"name"`
+	MemberCount int                     `json:"member_count,omitempty"`
+	Members     []PermissionsMembership `json:"members,omitempty"`
+}
+
+type PermissionsMembership struct {
+	MembershipId int `json:"membership_id,omitempty"`
+	GroupId      int `json:"group_id"`
+	UserId       int `json:"user_id"`
+}
+
+// PermissionsGraph is the data permissions graph of /api/permissions/graph,
+// groups are keyed by group ID and then by database ID
+type PermissionsGraph struct {
+	Revision int                                          `json:"revision"`
+	Groups   map[string]map[string]map[string]interface{} `json:"groups"`
+}
+
+type Collection struct {
+	Id             int     `json:"id,omitempty"`
+	Name           string  `json:"name"`
+	Description    *string `json:"description"`
+	Color          string  `json:"color,omitempty"`
+	ParentId       *int    `json:"parent_id"`
+	AuthorityLevel *string `json:"authority_level"`
+	Archived       bool    `json:"archived"`
+	Location       string  `json:"location,omitempty"`
+	Slug           string  `json:"slug,omitempty"`
+}
+
+// CollectionGraph is the collection permissions graph of /api/collection/graph,
+// groups are keyed by group ID and then by collection ID or "root"
+type CollectionGraph struct {
+	Revision int                          `json:"revision"`
+	Groups   map[string]map[string]string `json:"groups"`
+}
+
+type Card struct {
+	Id                    int             `json:"id,omitempty"`
+	Name                  string          `json:"name"`
+	Description           *string         `json:"description"`
+	CollectionId          *int            `json:"collection_id"`
+	Display               string          `json:"display"`
+	DatasetQuery          json.RawMessage `json:"dataset_query"`
+	VisualizationSettings json.RawMessage `json:"visualization_settings"`
+	Type                  string          `json:"type,omitempty"`
+	// older Metabase versions mark models with dataset instead of type
+	Dataset    bool   `json:"dataset"`
+	CacheTtl   *int   `json:"cache_ttl"`
+	DatabaseId int    `json:"database_id,omitempty"`
+	TableId    *int   `json:"table_id,omitempty"`
+	QueryType  string `json:"query_type,omitempty"`
+}
+
+type Dashboard struct {
+	Id           int             `json:"id,omitempty"`
+	Name         string          `json:"name"`
+	Description  *string         `json:"description"`
+	CollectionId *int            `json:"collection_id"`
+	Parameters   json.RawMessage `json:"parameters"`
+	Tabs         []DashboardTab  `json:"tabs,omitempty"`
+	Dashcards    []DashboardCard `json:"dashcards,omitempty"`
+	// Metabase 0.47 returns dashboard cards as ordered_cards and tabs as ordered_tabs
+	OrderedCards []DashboardCard `json:"ordered_cards,omitempty"`
+	OrderedTabs  []DashboardTab  `json:"ordered_tabs,omitempty"`
+}
+
+type DashboardTab struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position,omitempty"`
+}
+
+type DashboardCard struct {
+	Id                    int                   `json:"id"`
+	CardId                *int                  `json:"card_id"`
+	DashboardTabId        *int                  `json:"dashboard_tab_id"`
+	Row                   int                   `json:"row"`
+	Col                   int                   `json:"col"`
+	SizeX                 int                   `json:"size_x"`
+	SizeY                 int                   `json:"size_y"`
+	ParameterMappings     json.RawMessage       `json:"parameter_mappings"`
+	Series                []DashboardCardSeries `json:"series"`
+	VisualizationSettings json.RawMessage       `json:"visualization_settings"`
+}
+
+type DashboardCardSeries struct {
+	Id int `json:"id"`
+}
+
+// DashboardCards replaces all cards and tabs of a dashboard,
+// new ones are given negative IDs
+type DashboardCards struct {
+	Cards []DashboardCard `json:"cards"`
+	Tabs  []DashboardTab  `json:"tabs"`
+	// Metabase 0.47 only knows tabs as ordered_tabs
+	OrderedTabs []DashboardTab `json:"ordered_tabs"`
+}
+
+type Pulse struct {
+	Id           int             `json:"id,omitempty"`
+	Name         string          `json:"name"`
+	DashboardId  *int            `json:"dashboard_id,omitempty"`
+	CollectionId *int            `json:"collection_id"`
+	Cards        []PulseCard     `json:"cards"`
+	Channels     []PulseChannel  `json:"channels"`
+	SkipIfEmpty  bool            `json:"skip_if_empty"`
+	Parameters   json.RawMessage `json:"parameters,omitempty"`
+	Archived     bool            `json:"archived"`
+}
+
+type PulseCard struct {
+	Id              int  `json:"id"`
+	IncludeCsv      bool `json:"include_csv"`
+	IncludeXls      bool `json:"include_xls"`
+	DashboardCardId *int `json:"dashboard_card_id,omitempty"`
+}
+
+// PulseChannel is an email or Slack channel of a dashboard subscription or an alert
+type PulseChannel struct {
+	Id            int                    `json:"id,omitempty"`
+	ChannelType   string                 `json:"channel_type"`
+	Enabled       bool                   `json:"enabled"`
+	ScheduleType  string                 `json:"schedule_type"`
+	ScheduleHour  *int                   `json:"schedule_hour"`
+	ScheduleDay   *string                `json:"schedule_day"`
+	ScheduleFrame *string                `json:"schedule_frame"`
+	Recipients    []PulseRecipient       `json:"recipients"`
+	Details       map[string]interface{} `json:"details"`
+}
+
+// PulseRecipient is either a Metabase user given by ID or an external email address
+type PulseRecipient struct {
+	Id    int    `json:"id,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type Alert struct {
+	Id             int            `json:"id,omitempty"`
+	Card           PulseCard      `json:"card"`
+	Channels       []PulseChannel `json:"channels"`
+	AlertCondition string         `json:"alert_condition"`
+	AlertFirstOnly bool           `json:"alert_first_only"`
+	AlertAboveGoal *bool          `json:"alert_above_goal"`
+	Archived       bool           `json:"archived"`
+}
+
+// TableDefinition is a segment or a legacy metric of a table
+type TableDefinition struct {
+	Id              int             `json:"id,omitempty"`
+	Name            string          `json:"name"`
+	Description     *string         `json:"description"`
+	TableId         int             `json:"table_id,omitempty"`
+	Definition      json.RawMessage `json:"definition"`
+	RevisionMessage string          `json:"revision_message,omitempty"`
+	Archived        bool            `json:"archived"`
+}
+
+// Setting is an admin setting as listed by GET /api/setting
+type Setting struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
 }
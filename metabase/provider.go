@@ -2,6 +2,8 @@ package metabase
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -32,11 +34,36 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"metabase_database": resourceDatabase(),
+			"metabase_database":                     resourceDatabase(),
+			"metabase_user":                         resourceUser(),
+			"metabase_permissions_group":            resourcePermissionsGroup(),
+			"metabase_permissions_group_membership": resourcePermissionsGroupMembership(),
+			"metabase_permissions_group_members":    resourcePermissionsGroupMembers(),
+			"metabase_database_permissions":         resourceDatabasePermissions(),
+			"metabase_collection":                   resourceCollection(),
+			"metabase_collection_permissions":       resourceCollectionPermissions(),
+			"metabase_card":                         resourceCard(),
+			"metabase_dashboard":                    resourceDashboard(),
+			"metabase_dashboard_subscription":       resourceDashboardSubscription(),
+			"metabase_alert":                        resourceAlert(),
+			"metabase_segment":                      resourceSegment(),
+			"metabase_metric":                       resourceMetric(),
+			"metabase_table":                        resourceTable(),
+			"metabase_field":                        resourceField(),
+			"metabase_setting":                      resourceSetting(),
+			"metabase_settings":                     resourceSettings(),
+			"metabase_email_settings":               resourceEmailSettings(),
+			"metabase_slack_settings":               resourceSlackSettings(),
+			"metabase_ldap_settings":                resourceLdapSettings(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"metabase_bases": dataSourceBases(),
-			"metabase_base":  dataSourceBase(),
+			"metabase_bases":             dataSourceBases(),
+			"metabase_base":              dataSourceBase(),
+			"metabase_permissions_group": dataSourcePermissionsGroup(),
+			"metabase_schemas":           dataSourceSchemas(),
+			"metabase_tables":            dataSourceTables(),
+			"metabase_table":             dataSourceTable(),
+			"metabase_field":             dataSourceField(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -51,7 +78,7 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	var diags diag.Diagnostics
 
 	if (username != "") && (password != "") && (url != "") {
-		c, err := NewClient(&url, &username, &password)
+		c, err := NewClient(ctx, &url, &username, &password)
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
@@ -59,10 +86,46 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return c, diags
 	}
 
-	c, err := NewClient(nil, nil, nil)
+	c, err := NewClient(ctx, nil, nil, nil)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
 
 	return c, diags
 }
+
+// apiErrorDiags turns an error returned by checkResponse into diagnostics,
+// notFound is used as the summary when Metabase responds with 404
+func apiErrorDiags(err error, notFound string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return diag.FromErr(err)
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  notFound,
+			Detail:   apiErr.Message,
+		})
+	case http.StatusUnauthorized, http.StatusForbidden:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Permission denied",
+			Detail: fmt.Sprintf("%s\n\nMetabase responded with status %d. "+
+				"Most of the API requires the provider to be configured with an administrator account.",
+				apiErr.Message, apiErr.StatusCode),
+		})
+	default:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Metabase responded with status %d", apiErr.StatusCode),
+			Detail:   apiErr.Message,
+		})
+	}
+
+	return diags
+}
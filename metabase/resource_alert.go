@@ -0,0 +1,238 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	alertRows      = "rows"
+	alertGoalAbove = "goal_above"
+	alertGoalBelow = "goal_below"
+)
+
+// goalDisplays are card visualizations which can have a goal line
+var goalDisplays = []string{"line", "area", "bar", "combo", "progress"}
+
+func resourceAlert() *schema.Resource {
+	s := pulseChannelsSchema()
+	s["card_id"] = &schema.Schema{
+		Description: "ID of the card an alert watches",
+		Type:        schema.TypeInt,
+		Required:    true,
+	}
+	s["condition"] = &schema.Schema{
+		Description: "When to alert: `rows` when a card has results, `goal_above` or `goal_below` " +
+			"when results cross the goal line of a card",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      alertRows,
+		ValidateFunc: validation.StringInSlice([]string{alertRows, alertGoalAbove, alertGoalBelow}, false),
+	}
+	s["first_only"] = &schema.Schema{
+		Description: "Whether to send an alert only the first time the condition is met",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+
+	return &schema.Resource{
+		Description: "`metabase_alert` resource can be used for managing alerts on saved questions.\n\n" +
+			"Goal conditions are only available for cards displayed as a line, area, bar, combo or progress chart " +
+			"with a goal set in their visualization settings: `graph.goal_value`, or `progress.goal` for progress charts. " +
+			"Destroying the resource archives the alert.",
+		CreateContext: resourceAlertCreate,
+		ReadContext:   resourceAlertRead,
+		UpdateContext: resourceAlertUpdate,
+		DeleteContext: resourceAlertDelete,
+		CustomizeDiff: resourcePulseCustomizeDiff,
+		Schema:        s,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+// checkGoalCard returns an error when a card cannot be used by goal alerts.
+// It runs on apply, after changes to the card of the same run are applied.
+func checkGoalCard(ctx context.Context, c *Client, cardId int) error {
+	card := &Card{}
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/card/%d", cardId), nil, card); err != nil {
+		return fmt.Errorf("unable to read card %d: %w", cardId, err)
+	}
+
+	supported := false
+	for _, display := range goalDisplays {
+		if card.Display == display {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("goal alerts require a card displayed as one of %v, card %d is displayed as %q",
+			goalDisplays, cardId, card.Display)
+	}
+
+	// progress charts keep their goal apart from the other charts
+	goalKey := "graph.goal_value"
+	if card.Display == "progress" {
+		goalKey = "progress.goal"
+	}
+
+	var settings map[string]interface{}
+	if len(card.VisualizationSettings) > 0 {
+		if err := json.Unmarshal(card.VisualizationSettings, &settings); err != nil {
+			return fmt.Errorf("unable to decode visualization settings of card %d: %w", cardId, err)
+		}
+	}
+	if settings[goalKey] == nil {
+		return fmt.Errorf("goal alerts require a goal, card %d has no %q visualization setting", cardId, goalKey)
+	}
+
+	return nil
+}
+
+func resourceAlertCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	alert, diags := expandAlert(ctx, c, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	created := &Alert{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/alert", alert, created)
+	if err != nil {
+		return apiErrorDiags(err, "Card or recipients of the alert not found")
+	}
+
+	d.SetId(strconv.Itoa(created.Id))
+
+	return resourceAlertRead(ctx, d, m)
+}
+
+func resourceAlertRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	alert := &Alert{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/alert/%s", d.Id()), nil, alert)
+	if isNotFound(err) || (err == nil && alert.Archived) {
+		tflog.Warn(ctx, "Alert is deleted or archived, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Alert %s not found", d.Id()))
+	}
+
+	for k, v := range flattenAlert(alert) {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign alert attribute to d.%s in resourceAlertRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceAlertUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("card_id", "condition", "first_only", channelEmail, channelSlack, "schedule") {
+		alert, diags := expandAlert(ctx, c, d)
+		if diags.HasError() {
+			return diags
+		}
+
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/alert/%s", d.Id()), alert, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Alert %s not found", d.Id()))
+		}
+	}
+
+	return resourceAlertRead(ctx, d, m)
+}
+
+func resourceAlertDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/alert/%s", d.Id()), map[string]interface{}{
+		"archived": true,
+	}, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Alert %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandAlert(ctx context.Context, c *Client, d *schema.ResourceData) (*Alert, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	cardId := d.Get("card_id").(int)
+	condition := d.Get("condition").(string)
+
+	if condition != alertRows {
+		if err := checkGoalCard(ctx, c, cardId); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid alert condition",
+				Detail:   err.Error(),
+			})
+			return nil, diags
+		}
+	}
+
+	alert := &Alert{
+		Card:           PulseCard{Id: cardId},
+		Channels:       expandPulseChannels(d),
+		AlertCondition: "rows",
+		AlertFirstOnly: d.Get("first_only").(bool),
+	}
+
+	if condition != alertRows {
+		above := condition == alertGoalAbove
+		alert.AlertCondition = "goal"
+		alert.AlertAboveGoal = &above
+	}
+
+	return alert, diags
+}
+
+func flattenAlert(alert *Alert) map[string]interface{} {
+	oi := flattenPulseChannels(alert.Channels)
+
+	oi["card_id"] = alert.Card.Id
+	oi["condition"] = alertRows
+	if alert.AlertCondition == "goal" {
+		oi["condition"] = alertGoalBelow
+		if alert.AlertAboveGoal != nil && *alert.AlertAboveGoal {
+			oi["condition"] = alertGoalAbove
+		}
+	}
+	oi["first_only"] = alert.AlertFirstOnly
+
+	return oi
+}
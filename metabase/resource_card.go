@@ -0,0 +1,256 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCard() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_card` resource can be used for managing saved questions, models and metrics.\n\n" +
+			"Queries are given as JSON, use `jsonencode` to reference other resources in them.",
+		CreateContext: resourceCardCreate,
+		ReadContext:   resourceCardRead,
+		UpdateContext: resourceCardUpdate,
+		DeleteContext: resourceCardDelete,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Description: "Name of a card",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Description of a card",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"collection_id": &schema.Schema{
+				Description: "ID of a collection, the root collection when not set",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"type": &schema.Schema{
+				Description:  "Type of a card: `question`, `model` or `metric`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "question",
+				ValidateFunc: validation.StringInSlice([]string{"question", "model", "metric"}, false),
+			},
+			"display": &schema.Schema{
+				Description: "Visualization of a card, e.g. `table`, `bar`, `line` or `scalar`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "table",
+			},
+			"dataset_query": &schema.Schema{
+				Description: "Query of a card as JSON, either native " +
+					"(`{\"type\": \"native\", \"database\": 1, \"native\": {\"query\": \"SELECT 1\"}}`) or MBQL " +
+					"(`{\"type\": \"query\", \"database\": 1, \"query\": {\"source-table\": 2}}`)",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				StateFunc:        jsonStateFunc,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"visualization_settings": &schema.Schema{
+				Description:      "Visualization settings of a card as JSON",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				ValidateFunc:     validation.StringIsJSON,
+				StateFunc:        jsonStateFunc,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"cache_ttl": &schema.Schema{
+				Description:  "Cache TTL multiplier of a card, the database or site default when not set",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"database_id": &schema.Schema{
+				Description: "ID of the database a card queries",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"table_id": &schema.Schema{
+				Description: "ID of the table an MBQL card queries",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"query_type": &schema.Schema{
+				Description: "Type of the query of a card: `native` or `query`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceCardCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	card := &Card{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/card", expandCard(d), card)
+	if err != nil {
+		return apiErrorDiags(err, "Database, table or collection of the card not found")
+	}
+
+	d.SetId(strconv.Itoa(card.Id))
+
+	return resourceCardRead(ctx, d, m)
+}
+
+func resourceCardRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	card := &Card{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/card/%s", d.Id()), nil, card)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Card is deleted, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Card %s not found", d.Id()))
+	}
+
+	flattened, err := flattenCard(card)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to normalize JSON in resourceCardRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign card attribute to d.%s in resourceCardRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceCardUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("name", "description", "collection_id", "type", "display", "dataset_query", "visualization_settings", "cache_ttl") {
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/card/%s", d.Id()), expandCard(d), nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Card %s not found", d.Id()))
+		}
+	}
+
+	return resourceCardRead(ctx, d, m)
+}
+
+func resourceCardDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/card/%s", d.Id()), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Card %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandCard(d *schema.ResourceData) *Card {
+	card := &Card{
+		Name:                  d.Get("name").(string),
+		Display:               d.Get("display").(string),
+		Type:                  d.Get("type").(string),
+		Dataset:               d.Get("type").(string) == "model",
+		DatasetQuery:          json.RawMessage(d.Get("dataset_query").(string)),
+		VisualizationSettings: json.RawMessage(d.Get("visualization_settings").(string)),
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		s := description.(string)
+		card.Description = &s
+	}
+
+	if collectionId, ok := d.GetOk("collection_id"); ok {
+		id := collectionId.(int)
+		card.CollectionId = &id
+	}
+
+	if cacheTtl, ok := d.GetOk("cache_ttl"); ok {
+		ttl := cacheTtl.(int)
+		card.CacheTtl = &ttl
+	}
+
+	return card
+}
+
+func flattenCard(card *Card) (map[string]interface{}, error) {
+	oi := make(map[string]interface{})
+
+	oi["name"] = card.Name
+	oi["description"] = ""
+	if card.Description != nil {
+		oi["description"] = *card.Description
+	}
+	oi["collection_id"] = 0
+	if card.CollectionId != nil {
+		oi["collection_id"] = *card.CollectionId
+	}
+	oi["type"] = card.Type
+	if card.Type == "" {
+		oi["type"] = "question"
+		if card.Dataset {
+			oi["type"] = "model"
+		}
+	}
+	oi["display"] = card.Display
+	datasetQuery, err := flattenJSON(card.DatasetQuery, "{}")
+	if err != nil {
+		return nil, err
+	}
+	oi["dataset_query"] = datasetQuery
+	visualizationSettings, err := flattenJSON(card.VisualizationSettings, "{}")
+	if err != nil {
+		return nil, err
+	}
+	oi["visualization_settings"] = visualizationSettings
+	oi["cache_ttl"] = 0
+	if card.CacheTtl != nil {
+		oi["cache_ttl"] = *card.CacheTtl
+	}
+	oi["database_id"] = card.DatabaseId
+	oi["table_id"] = 0
+	if card.TableId != nil {
+		oi["table_id"] = *card.TableId
+	}
+	oi["query_type"] = card.QueryType
+
+	return oi, nil
+}
@@ -0,0 +1,230 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// defaultCollectionColor is used by the UI, older Metabase versions require a color
+const defaultCollectionColor = "#509EE3"
+
+// collectionIdRegexp matches IDs of collections including the root collection
+var collectionIdRegexp = regexp.MustCompile(`^(root|[0-9]+)$`)
+
+func resourceCollection() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_collection` resource can be used for managing collections of questions and dashboards.\n\n" +
+			"Metabase doesn't delete collections: destroying the resource archives the collection.",
+		CreateContext: resourceCollectionCreate,
+		ReadContext:   resourceCollectionRead,
+		UpdateContext: resourceCollectionUpdate,
+		DeleteContext: resourceCollectionDelete,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Description: "Name of a collection",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Description of a collection",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"color": &schema.Schema{
+				Description:  "Color of a collection in hex format, e.g. `#509EE3`. Ignored by newer Metabase versions",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`), "must be a hex color like #509EE3"),
+			},
+			"parent_id": &schema.Schema{
+				Description: "ID of a parent collection, the root collection when not set",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"official": &schema.Schema{
+				Description: "Whether a collection is marked as official (`authority_level` of Metabase)",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"archived": &schema.Schema{
+				Description: "Whether a collection is archived",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"location": &schema.Schema{
+				Description: "Path of IDs of ancestor collections, e.g. `/1/5/`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"slug": &schema.Schema{
+				Description: "Slug of a collection",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceCollectionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	collection := expandCollection(d)
+	if collection.Color == "" {
+		collection.Color = defaultCollectionColor
+	}
+
+	created := &Collection{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/collection", collection, created)
+	if err != nil {
+		return apiErrorDiags(err, "Parent collection not found")
+	}
+
+	d.SetId(strconv.Itoa(created.Id))
+
+	// a collection can't be created archived
+	if collection.Archived {
+		err = c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/collection/%s", d.Id()), collection, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Collection %s not found", d.Id()))
+		}
+	}
+
+	return resourceCollectionRead(ctx, d, m)
+}
+
+func resourceCollectionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	collection := &Collection{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/collection/%s", d.Id()), nil, collection)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Collection is deleted, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Collection %s not found", d.Id()))
+	}
+
+	for k, v := range flattenCollection(collection) {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign collection attribute to d.%s in resourceCollectionRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceCollectionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("name", "description", "color", "parent_id", "official", "archived") {
+		// Metabase moves the collection with its descendants when parent_id changes
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/collection/%s", d.Id()), expandCollection(d), nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Collection %s or its parent not found", d.Id()))
+		}
+	}
+
+	return resourceCollectionRead(ctx, d, m)
+}
+
+func resourceCollectionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	collection := expandCollection(d)
+	collection.Archived = true
+
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/collection/%s", d.Id()), collection, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Collection %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandCollection(d *schema.ResourceData) *Collection {
+	collection := &Collection{
+		Name:     d.Get("name").(string),
+		Color:    d.Get("color").(string),
+		Archived: d.Get("archived").(bool),
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		s := description.(string)
+		collection.Description = &s
+	}
+
+	if parentId, ok := d.GetOk("parent_id"); ok {
+		id := parentId.(int)
+		collection.ParentId = &id
+	}
+
+	if d.Get("official").(bool) {
+		official := "official"
+		collection.AuthorityLevel = &official
+	}
+
+	return collection
+}
+
+func flattenCollection(collection *Collection) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	oi["name"] = collection.Name
+	oi["description"] = ""
+	if collection.Description != nil {
+		oi["description"] = *collection.Description
+	}
+	oi["color"] = collection.Color
+	oi["parent_id"] = collectionParentId(collection.Location)
+	oi["official"] = collection.AuthorityLevel != nil && *collection.AuthorityLevel == "official"
+	oi["archived"] = collection.Archived
+	oi["location"] = collection.Location
+	oi["slug"] = collection.Slug
+
+	return oi
+}
+
+// collectionParentId returns the last ID of a location like /1/5/,
+// or 0 for collections in the root collection
+func collectionParentId(location string) int {
+	ids := strings.Split(strings.Trim(location, "/"), "/")
+
+	id, err := strconv.Atoi(ids[len(ids)-1])
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
@@ -0,0 +1,167 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCollectionPermissions() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_collection_permissions` resource can be used for managing access of a permissions group to a collection.\n\n" +
+			"Only the cell of the group and the collection in the collection permissions graph is managed, " +
+			"so other groups and collections can be managed in the UI or by other resources.",
+		CreateContext: resourceCollectionPermissionsCreate,
+		ReadContext:   resourceCollectionPermissionsRead,
+		UpdateContext: resourceCollectionPermissionsUpdate,
+		DeleteContext: resourceCollectionPermissionsDelete,
+		Schema: map[string]*schema.Schema{
+			"group_id": &schema.Schema{
+				Description:  "ID of a permissions group",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntNotInSlice([]int{administratorGroupId}),
+			},
+			"collection_id": &schema.Schema{
+				Description:  "ID of a collection, or `root` for the root collection",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(collectionIdRegexp, "must be an ID of a collection or root"),
+			},
+			"permission": &schema.Schema{
+				Description:  "Access of a group to a collection: `read`, `write` or `none`",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"read", "write", "none"}, false),
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceCollectionPermissionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(fmt.Sprintf("%d:%s", d.Get("group_id").(int), d.Get("collection_id").(string)))
+
+	if diags := writeCollectionPermission(ctx, d, m, d.Get("permission").(string)); diags.HasError() {
+		d.SetId("")
+		return diags
+	}
+
+	return resourceCollectionPermissionsRead(ctx, d, m)
+}
+
+func resourceCollectionPermissionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	groupId, collectionId, err := parseCollectionPermissionsId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	graph := &CollectionGraph{}
+	if err := c.doJSON(ctx, http.MethodGet, "/api/collection/graph", nil, graph); err != nil {
+		return apiErrorDiags(err, "Collection permissions graph not found")
+	}
+
+	// Metabase leaves out collections a group has no access to
+	permission, ok := graph.Groups[groupId][collectionId]
+	if !ok {
+		permission = "none"
+	}
+
+	group, _ := strconv.Atoi(groupId)
+
+	for k, v := range map[string]interface{}{
+		"group_id":      group,
+		"collection_id": collectionId,
+		"permission":    permission,
+	} {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign permissions attribute to d.%s in resourceCollectionPermissionsRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceCollectionPermissionsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("permission") {
+		if diags := writeCollectionPermission(ctx, d, m, d.Get("permission").(string)); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceCollectionPermissionsRead(ctx, d, m)
+}
+
+func resourceCollectionPermissionsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := writeCollectionPermission(ctx, d, m, "none"); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// writeCollectionPermission submits the permission of one group to one collection
+// along with the revision of the graph it was merged into
+func writeCollectionPermission(ctx context.Context, d *schema.ResourceData, m interface{}, permission string) diag.Diagnostics {
+	c := m.(*Client)
+
+	groupId, collectionId, err := parseCollectionPermissionsId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.updateGraph(ctx, "/api/collection/graph", func(current []byte) (interface{}, error) {
+		graph := &CollectionGraph{}
+		if err := json.Unmarshal(current, graph); err != nil {
+			return nil, err
+		}
+
+		return &CollectionGraph{
+			Revision: graph.Revision,
+			Groups: map[string]map[string]string{
+				groupId: {collectionId: permission},
+			},
+		}, nil
+	})
+	if err != nil {
+		return apiErrorDiags(err, "Collection permissions graph not found")
+	}
+
+	return nil
+}
+
+func parseCollectionPermissionsId(id string) (string, string, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("unexpected ID %q, expected <group_id>:<collection_id>", id)
+	}
+
+	if _, err := strconv.Atoi(parts[0]); err != nil || !collectionIdRegexp.MatchString(parts[1]) {
+		return "", "", fmt.Errorf("unexpected ID %q, expected <group_id>:<collection_id>", id)
+	}
+
+	return parts[0], parts[1], nil
+}
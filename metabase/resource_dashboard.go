@@ -0,0 +1,580 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dashboardTabsMinorVersion is the first Metabase version accepting tabs
+// and new cards with placeholder IDs in PUT /api/dashboard/:id/cards:
+// dashboard tabs were released with Metabase 0.47
+const dashboardTabsMinorVersion = 47
+
+func resourceDashboard() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_dashboard` resource can be used for managing dashboards with their cards, tabs and filters.\n\n" +
+			"Cards keep the order of the `card` blocks, imported dashboards list them by tab, `row` and `col`. " +
+			"Existing dashboard cards are matched by `card_id`, so moving or resizing a card updates it in place.\n\n" +
+			"Cards and tabs require Metabase 0.47 or later.",
+		CreateContext: resourceDashboardCreate,
+		ReadContext:   resourceDashboardRead,
+		UpdateContext: resourceDashboardUpdate,
+		DeleteContext: resourceDashboardDelete,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Description: "Name of a dashboard",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Description of a dashboard",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"collection_id": &schema.Schema{
+				Description: "ID of a collection, the root collection when not set",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"parameters": &schema.Schema{
+				Description: "Filters of a dashboard as a JSON array, " +
+					"e.g. `[{\"id\": \"date\", \"name\": \"Date\", \"slug\": \"date\", \"type\": \"date/all-options\"}]`",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "[]",
+				ValidateFunc:     validation.StringIsJSON,
+				StateFunc:        jsonStateFunc,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"tab": &schema.Schema{
+				Description: "Tabs of a dashboard in display order",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Description: "Name of a tab, unique within a dashboard",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+					},
+				},
+			},
+			"card": &schema.Schema{
+				Description: "Cards placed on a dashboard",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"card_id": &schema.Schema{
+							Description: "ID of a card, not set for text and heading cards",
+							Type:        schema.TypeInt,
+							Optional:    true,
+						},
+						"tab": &schema.Schema{
+							Description: "Name of the tab a card is placed on, the first tab when not set",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+						},
+						"row": &schema.Schema{
+							Description:  "Row of the top left corner of a card",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"col": &schema.Schema{
+							Description:  "Column of the top left corner of a card",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"size_x": &schema.Schema{
+							Description:  "Width of a card in columns",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"size_y": &schema.Schema{
+							Description:  "Height of a card in rows",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"parameter_mappings": &schema.Schema{
+							Description: "Mappings of dashboard filters to a card as a JSON array, " +
+								"e.g. `[{\"parameter_id\": \"date\", \"card_id\": 1, \"target\": [\"variable\", [\"template-tag\", \"date\"]]}]`",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "[]",
+							ValidateFunc:     validation.StringIsJSON,
+							StateFunc:        jsonStateFunc,
+							DiffSuppressFunc: suppressEquivalentJSON,
+						},
+						"series": &schema.Schema{
+							Description: "IDs of cards added to a card as additional series",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+						"visualization_settings": &schema.Schema{
+							Description:      "Visualization settings overriding the ones of a card as JSON",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "{}",
+							ValidateFunc:     validation.StringIsJSON,
+							StateFunc:        jsonStateFunc,
+							DiffSuppressFunc: suppressEquivalentJSON,
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceDashboardCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	hasCards := len(d.Get("tab").([]interface{})) > 0 || len(d.Get("card").([]interface{})) > 0
+	// fail before creating a dashboard its cards cannot be added to
+	if hasCards {
+		if diags := checkDashboardCardsVersion(ctx, c); diags.HasError() {
+			return diags
+		}
+	}
+
+	dashboard := &Dashboard{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/dashboard", expandDashboard(d), dashboard)
+	if err != nil {
+		return apiErrorDiags(err, "Collection of the dashboard not found")
+	}
+
+	d.SetId(strconv.Itoa(dashboard.Id))
+
+	if hasCards {
+		if diags := writeDashboardCards(ctx, c, d); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceDashboardRead(ctx, d, m)
+}
+
+func resourceDashboardRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	dashboard, err := readDashboard(ctx, c, d.Id())
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Dashboard is deleted, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Dashboard %s not found", d.Id()))
+	}
+
+	flattened, err := flattenDashboard(dashboard, d.Get("card").([]interface{}))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to normalize JSON in resourceDashboardRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign dashboard attribute to d.%s in resourceDashboardRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceDashboardUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// filters are updated first, so cards can be mapped to new ones
+	if d.HasChanges("name", "description", "collection_id", "parameters") {
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/dashboard/%s", d.Id()), expandDashboard(d), nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Dashboard %s not found", d.Id()))
+		}
+	}
+
+	if d.HasChanges("tab", "card") {
+		if diags := writeDashboardCards(ctx, c, d); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceDashboardRead(ctx, d, m)
+}
+
+func resourceDashboardDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/dashboard/%s", d.Id()), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Dashboard %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func readDashboard(ctx context.Context, c *Client, id string) (*Dashboard, error) {
+	dashboard := &Dashboard{}
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/dashboard/%s", id), nil, dashboard); err != nil {
+		return nil, err
+	}
+
+	if len(dashboard.Dashcards) == 0 {
+		dashboard.Dashcards = dashboard.OrderedCards
+	}
+	if len(dashboard.Tabs) == 0 {
+		dashboard.Tabs = dashboard.OrderedTabs
+	}
+
+	return dashboard, nil
+}
+
+// writeDashboardCards replaces cards and tabs of a dashboard with the configured ones.
+// Existing tabs are matched by name and existing cards by card ID, so they are
+// updated in place instead of being recreated.
+func writeDashboardCards(ctx context.Context, c *Client, d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if diags := checkDashboardCardsVersion(ctx, c); diags.HasError() {
+		return diags
+	}
+
+	current, err := readDashboard(ctx, c, d.Id())
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Dashboard %s not found", d.Id()))
+	}
+
+	cards, err := expandDashboardCards(d, current)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid dashboard cards",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	err = c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/dashboard/%s/cards", d.Id()), cards, nil)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Dashboard %s or one of its cards not found", d.Id()))
+	}
+
+	return diags
+}
+
+// checkDashboardCardsVersion fails on Metabase versions without dashboard tabs,
+// which do not accept placeholder IDs for new cards either
+func checkDashboardCardsVersion(ctx context.Context, c *Client) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := c.requireVersion(ctx, dashboardTabsMinorVersion, "Managing dashboard cards and tabs"); err != nil {
+		if _, ok := err.(*APIError); ok {
+			return apiErrorDiags(err, "Metabase version not found")
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unsupported Metabase version",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	return diags
+}
+
+func expandDashboard(d *schema.ResourceData) *Dashboard {
+	dashboard := &Dashboard{
+		Name:       d.Get("name").(string),
+		Parameters: json.RawMessage(d.Get("parameters").(string)),
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		s := description.(string)
+		dashboard.Description = &s
+	}
+
+	if collectionId, ok := d.GetOk("collection_id"); ok {
+		id := collectionId.(int)
+		dashboard.CollectionId = &id
+	}
+
+	return dashboard
+}
+
+func expandDashboardCards(d *schema.ResourceData, current *Dashboard) (*DashboardCards, error) {
+	cards := &DashboardCards{
+		Cards: []DashboardCard{},
+		Tabs:  []DashboardTab{},
+	}
+
+	existingTabs := make(map[string]int, len(current.Tabs))
+	for _, tab := range current.Tabs {
+		existingTabs[tab.Name] = tab.Id
+	}
+
+	tabIds := make(map[string]int)
+	for i, v := range d.Get("tab").([]interface{}) {
+		name := v.(map[string]interface{})["name"].(string)
+		if _, ok := tabIds[name]; ok {
+			return nil, fmt.Errorf("tab %q is defined more than once", name)
+		}
+
+		id, ok := existingTabs[name]
+		if !ok {
+			id = -(i + 1)
+		}
+		tabIds[name] = id
+		cards.Tabs = append(cards.Tabs, DashboardTab{Id: id, Name: name})
+	}
+
+	used := make(map[int]bool, len(current.Dashcards))
+	for i, v := range d.Get("card").([]interface{}) {
+		block := v.(map[string]interface{})
+
+		card := DashboardCard{
+			Id:                    -(i + 1),
+			Row:                   block["row"].(int),
+			Col:                   block["col"].(int),
+			SizeX:                 block["size_x"].(int),
+			SizeY:                 block["size_y"].(int),
+			ParameterMappings:     json.RawMessage(block["parameter_mappings"].(string)),
+			Series:                []DashboardCardSeries{},
+			VisualizationSettings: json.RawMessage(block["visualization_settings"].(string)),
+		}
+
+		if cardId := block["card_id"].(int); cardId != 0 {
+			card.CardId = &cardId
+		}
+
+		for _, existing := range current.Dashcards {
+			if !used[existing.Id] && intPtrEqual(existing.CardId, card.CardId) {
+				used[existing.Id] = true
+				card.Id = existing.Id
+				break
+			}
+		}
+
+		if len(cards.Tabs) > 0 {
+			tab := block["tab"].(string)
+			if tab == "" {
+				tab = cards.Tabs[0].Name
+			}
+			tabId, ok := tabIds[tab]
+			if !ok {
+				return nil, fmt.Errorf("card %d is placed on tab %q which is not defined", i, tab)
+			}
+			card.DashboardTabId = &tabId
+		} else if block["tab"].(string) != "" {
+			return nil, fmt.Errorf("card %d is placed on tab %q but the dashboard has no tabs", i, block["tab"].(string))
+		}
+
+		for _, id := range block["series"].([]interface{}) {
+			card.Series = append(card.Series, DashboardCardSeries{Id: id.(int)})
+		}
+
+		cards.Cards = append(cards.Cards, card)
+	}
+
+	cards.OrderedTabs = cards.Tabs
+
+	return cards, nil
+}
+
+func intPtrEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// flattenDashboard returns the cards of a dashboard in the order of prior,
+// the card blocks of the configuration or state, followed by unknown cards
+func flattenDashboard(dashboard *Dashboard, prior []interface{}) (map[string]interface{}, error) {
+	oi := make(map[string]interface{})
+
+	oi["name"] = dashboard.Name
+	oi["description"] = ""
+	if dashboard.Description != nil {
+		oi["description"] = *dashboard.Description
+	}
+	oi["collection_id"] = 0
+	if dashboard.CollectionId != nil {
+		oi["collection_id"] = *dashboard.CollectionId
+	}
+	parameters, err := flattenJSON(dashboard.Parameters, "[]")
+	if err != nil {
+		return nil, err
+	}
+	oi["parameters"] = parameters
+
+	tabs := make([]DashboardTab, len(dashboard.Tabs))
+	copy(tabs, dashboard.Tabs)
+	sort.SliceStable(tabs, func(i, j int) bool {
+		return tabs[i].Position < tabs[j].Position
+	})
+
+	tabNames := make(map[int]string, len(tabs))
+	tabPositions := make(map[int]int, len(tabs))
+	flattenedTabs := make([]interface{}, len(tabs))
+	for i, tab := range tabs {
+		tabNames[tab.Id] = tab.Name
+		tabPositions[tab.Id] = i
+		flattenedTabs[i] = map[string]interface{}{
+			"name": tab.Name,
+		}
+	}
+	oi["tab"] = flattenedTabs
+
+	dashcards := make([]DashboardCard, len(dashboard.Dashcards))
+	copy(dashcards, dashboard.Dashcards)
+	tabPosition := func(card DashboardCard) int {
+		if card.DashboardTabId == nil {
+			return 0
+		}
+		return tabPositions[*card.DashboardTabId]
+	}
+	sort.SliceStable(dashcards, func(i, j int) bool {
+		a, b := dashcards[i], dashcards[j]
+		if tabPosition(a) != tabPosition(b) {
+			return tabPosition(a) < tabPosition(b)
+		}
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Col < b.Col
+	})
+	dashcards = orderDashboardCards(dashcards, prior, tabNames)
+
+	cards := make([]interface{}, len(dashcards))
+	for i, card := range dashcards {
+		parameterMappings, err := flattenJSON(card.ParameterMappings, "[]")
+		if err != nil {
+			return nil, err
+		}
+		visualizationSettings, err := flattenJSON(card.VisualizationSettings, "{}")
+		if err != nil {
+			return nil, err
+		}
+
+		cardId := 0
+		if card.CardId != nil {
+			cardId = *card.CardId
+		}
+		tab := ""
+		if card.DashboardTabId != nil {
+			tab = tabNames[*card.DashboardTabId]
+		}
+		series := make([]interface{}, len(card.Series))
+		for j, s := range card.Series {
+			series[j] = s.Id
+		}
+
+		cards[i] = map[string]interface{}{
+			"card_id":                cardId,
+			"tab":                    tab,
+			"row":                    card.Row,
+			"col":                    card.Col,
+			"size_x":                 card.SizeX,
+			"size_y":                 card.SizeY,
+			"parameter_mappings":     parameterMappings,
+			"series":                 series,
+			"visualization_settings": visualizationSettings,
+		}
+	}
+	oi["card"] = cards
+
+	return oi, nil
+}
+
+// orderDashboardCards orders dashcards like the prior card blocks. A block matches
+// the card with the same card_id at the same position, or else the first unmatched
+// card with the same card_id, so moved cards keep their place in the list.
+func orderDashboardCards(dashcards []DashboardCard, prior []interface{}, tabNames map[int]string) []DashboardCard {
+	used := make([]bool, len(dashcards))
+	matches := make([]int, len(prior))
+
+	samePosition := func(card DashboardCard, block map[string]interface{}) bool {
+		if tab := block["tab"].(string); tab != "" {
+			if card.DashboardTabId == nil || tabNames[*card.DashboardTabId] != tab {
+				return false
+			}
+		}
+		return card.Row == block["row"].(int) && card.Col == block["col"].(int)
+	}
+
+	for pass := 0; pass < 2; pass++ {
+		for i, v := range prior {
+			block, ok := v.(map[string]interface{})
+			if !ok || (pass == 1 && matches[i] != 0) {
+				continue
+			}
+
+			cardId := block["card_id"].(int)
+			for j, card := range dashcards {
+				if used[j] || (card.CardId == nil && cardId != 0) || (card.CardId != nil && *card.CardId != cardId) {
+					continue
+				}
+				if pass == 0 && !samePosition(card, block) {
+					continue
+				}
+				used[j] = true
+				matches[i] = j + 1
+				break
+			}
+		}
+	}
+
+	ordered := make([]DashboardCard, 0, len(dashcards))
+	for _, match := range matches {
+		if match != 0 {
+			ordered = append(ordered, dashcards[match-1])
+		}
+	}
+	for j, card := range dashcards {
+		if !used[j] {
+			ordered = append(ordered, card)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,479 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	channelEmail = "email"
+	channelSlack = "slack"
+)
+
+func resourceDashboardSubscription() *schema.Resource {
+	s := pulseChannelsSchema()
+	s["dashboard_id"] = &schema.Schema{
+		Description: "ID of the dashboard sent by a subscription",
+		Type:        schema.TypeInt,
+		Required:    true,
+		ForceNew:    true,
+	}
+	s["parameters"] = &schema.Schema{
+		Description: "Values of dashboard filters used by a subscription as a JSON array, " +
+			"e.g. `[{\"id\": \"date\", \"value\": \"past30days\"}]`",
+		Type:             schema.TypeString,
+		Optional:         true,
+		Default:          "[]",
+		ValidateFunc:     validation.StringIsJSON,
+		StateFunc:        jsonStateFunc,
+		DiffSuppressFunc: suppressEquivalentJSON,
+	}
+	s["skip_if_empty"] = &schema.Schema{
+		Description: "Whether to skip sending a subscription when all its cards have no results",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+
+	return &schema.Resource{
+		Description: "`metabase_dashboard_subscription` resource can be used for managing dashboard subscriptions " +
+			"sent by email or to Slack.\n\n" +
+			"A subscription sends the cards a dashboard has when the subscription is created or updated. " +
+			"Destroying the resource archives the subscription.",
+		CreateContext: resourceDashboardSubscriptionCreate,
+		ReadContext:   resourceDashboardSubscriptionRead,
+		UpdateContext: resourceDashboardSubscriptionUpdate,
+		DeleteContext: resourceDashboardSubscriptionDelete,
+		CustomizeDiff: resourcePulseCustomizeDiff,
+		Schema:        s,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+// pulseChannelsSchema returns the channels and the schedule shared
+// by dashboard subscriptions and alerts
+func pulseChannelsSchema() map[string]*schema.Schema {
+	channels := []string{channelEmail, channelSlack}
+
+	return map[string]*schema.Schema{
+		channelEmail: &schema.Schema{
+			Description:  "Email recipients",
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			AtLeastOneOf: channels,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"user_ids": &schema.Schema{
+						Description: "IDs of Metabase users receiving emails",
+						Type:        schema.TypeSet,
+						Optional:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeInt,
+						},
+					},
+					"addresses": &schema.Schema{
+						Description: "Email addresses of recipients who are not Metabase users",
+						Type:        schema.TypeSet,
+						Optional:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		channelSlack: &schema.Schema{
+			Description:  "Slack channel",
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			AtLeastOneOf: channels,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"channel": &schema.Schema{
+						Description: "Name of a Slack channel, e.g. `#kpis`, or a user, e.g. `@jane`",
+						Type:        schema.TypeString,
+						Required:    true,
+					},
+				},
+			},
+		},
+		"schedule": &schema.Schema{
+			Description: "When to send, shared by all channels",
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": &schema.Schema{
+						Description:  "Frequency: `hourly`, `daily`, `weekly` or `monthly`",
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"hourly", "daily", "weekly", "monthly"}, false),
+					},
+					"hour": &schema.Schema{
+						Description:  "Hour of the day to send at, in the report timezone. Required unless `hourly`, which does not use it",
+						Type:         schema.TypeInt,
+						Optional:     true,
+						ValidateFunc: validation.IntBetween(0, 23),
+					},
+					"day": &schema.Schema{
+						Description: "Day of the week to send on: `mon` to `sun`. Required for `weekly`, " +
+							"used with `frame` for `monthly`",
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringInSlice([]string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}, false),
+					},
+					"frame": &schema.Schema{
+						Description:  "Week of the month to send on: `first`, `mid` or `last`. Required for `monthly`",
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringInSlice([]string{"first", "mid", "last"}, false),
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourcePulseCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	schedules := d.Get("schedule").([]interface{})
+	if len(schedules) == 0 || schedules[0] == nil {
+		return nil
+	}
+	s := schedules[0].(map[string]interface{})
+
+	scheduleType := s["type"].(string)
+	day := s["day"].(string)
+	frame := s["frame"].(string)
+
+	// hour 0 is midnight, so only the configuration tells whether hour is set
+	if hourSet, known := scheduleHourSet(d); known {
+		if scheduleType == "hourly" && hourSet {
+			return fmt.Errorf("hour is not used with hourly schedules")
+		}
+		if scheduleType != "hourly" && !hourSet {
+			return fmt.Errorf("%s schedule requires hour", scheduleType)
+		}
+	}
+	if scheduleType == "weekly" && day == "" {
+		return fmt.Errorf("weekly schedule requires day")
+	}
+	if scheduleType == "monthly" && frame == "" {
+		return fmt.Errorf("monthly schedule requires frame")
+	}
+	if day != "" && scheduleType != "weekly" && scheduleType != "monthly" {
+		return fmt.Errorf("day is only used with weekly and monthly schedules, got %q", scheduleType)
+	}
+	if frame != "" && scheduleType != "monthly" {
+		return fmt.Errorf("frame is only used with monthly schedules, got %q", scheduleType)
+	}
+
+	return nil
+}
+
+// scheduleHourSet reports whether hour is set in the configured schedule,
+// known is false when the configuration is not available or not known yet
+func scheduleHourSet(d *schema.ResourceDiff) (set bool, known bool) {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false, false
+	}
+
+	schedules := config.GetAttr("schedule")
+	if schedules.IsNull() || !schedules.IsKnown() || schedules.LengthInt() == 0 {
+		return false, false
+	}
+
+	it := schedules.ElementIterator()
+	it.Next()
+	_, schedule := it.Element()
+
+	hour := schedule.GetAttr("hour")
+	if !hour.IsKnown() {
+		return false, false
+	}
+
+	return !hour.IsNull(), true
+}
+
+func resourceDashboardSubscriptionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	pulse, diags := expandDashboardSubscription(ctx, c, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	created := &Pulse{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/pulse", pulse, created)
+	if err != nil {
+		return apiErrorDiags(err, "Dashboard or recipients of the subscription not found")
+	}
+
+	d.SetId(strconv.Itoa(created.Id))
+
+	return resourceDashboardSubscriptionRead(ctx, d, m)
+}
+
+func resourceDashboardSubscriptionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	pulse := &Pulse{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/pulse/%s", d.Id()), nil, pulse)
+	if isNotFound(err) || (err == nil && pulse.Archived) {
+		tflog.Warn(ctx, "Dashboard subscription is deleted or archived, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Dashboard subscription %s not found", d.Id()))
+	}
+
+	flattened, err := flattenDashboardSubscription(pulse)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to normalize JSON in resourceDashboardSubscriptionRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign dashboard subscription attribute to d.%s in resourceDashboardSubscriptionRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceDashboardSubscriptionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges(channelEmail, channelSlack, "schedule", "parameters", "skip_if_empty") {
+		pulse, diags := expandDashboardSubscription(ctx, c, d)
+		if diags.HasError() {
+			return diags
+		}
+
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/pulse/%s", d.Id()), pulse, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Dashboard subscription %s not found", d.Id()))
+		}
+	}
+
+	return resourceDashboardSubscriptionRead(ctx, d, m)
+}
+
+func resourceDashboardSubscriptionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/pulse/%s", d.Id()), map[string]interface{}{
+		"archived": true,
+	}, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Dashboard subscription %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// expandDashboardSubscription builds a subscription sending all cards of its dashboard
+func expandDashboardSubscription(ctx context.Context, c *Client, d *schema.ResourceData) (*Pulse, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	dashboardId := d.Get("dashboard_id").(int)
+	dashboard, err := readDashboard(ctx, c, strconv.Itoa(dashboardId))
+	if err != nil {
+		return nil, apiErrorDiags(err, fmt.Sprintf("Dashboard %d not found", dashboardId))
+	}
+
+	pulse := &Pulse{
+		Name:         dashboard.Name,
+		DashboardId:  &dashboardId,
+		CollectionId: dashboard.CollectionId,
+		Cards:        []PulseCard{},
+		Channels:     expandPulseChannels(d),
+		SkipIfEmpty:  d.Get("skip_if_empty").(bool),
+		Parameters:   json.RawMessage(d.Get("parameters").(string)),
+	}
+
+	for _, dashcard := range dashboard.Dashcards {
+		if dashcard.CardId == nil {
+			continue
+		}
+		dashcardId := dashcard.Id
+		pulse.Cards = append(pulse.Cards, PulseCard{
+			Id:              *dashcard.CardId,
+			DashboardCardId: &dashcardId,
+		})
+	}
+
+	return pulse, diags
+}
+
+// expandPulseChannels returns the configured channels, each one sent on the same schedule
+func expandPulseChannels(d *schema.ResourceData) []PulseChannel {
+	s := d.Get("schedule").([]interface{})[0].(map[string]interface{})
+
+	schedule := PulseChannel{
+		Enabled:      true,
+		ScheduleType: s["type"].(string),
+		Recipients:   []PulseRecipient{},
+		Details:      map[string]interface{}{},
+	}
+	if schedule.ScheduleType != "hourly" {
+		hour := s["hour"].(int)
+		schedule.ScheduleHour = &hour
+	}
+	if day := s["day"].(string); day != "" {
+		schedule.ScheduleDay = &day
+	}
+	if frame := s["frame"].(string); frame != "" {
+		schedule.ScheduleFrame = &frame
+	}
+
+	var channels []PulseChannel
+
+	if v, ok := d.GetOk(channelEmail); ok && v.([]interface{})[0] != nil {
+		email := v.([]interface{})[0].(map[string]interface{})
+
+		channel := schedule
+		channel.ChannelType = channelEmail
+		channel.Recipients = []PulseRecipient{}
+		for _, id := range email["user_ids"].(*schema.Set).List() {
+			channel.Recipients = append(channel.Recipients, PulseRecipient{Id: id.(int)})
+		}
+		for _, address := range email["addresses"].(*schema.Set).List() {
+			channel.Recipients = append(channel.Recipients, PulseRecipient{Email: address.(string)})
+		}
+		channels = append(channels, channel)
+	}
+
+	if v, ok := d.GetOk(channelSlack); ok && v.([]interface{})[0] != nil {
+		slack := v.([]interface{})[0].(map[string]interface{})
+
+		channel := schedule
+		channel.ChannelType = channelSlack
+		channel.Details = map[string]interface{}{
+			"channel": slack["channel"].(string),
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels
+}
+
+func flattenDashboardSubscription(pulse *Pulse) (map[string]interface{}, error) {
+	oi := flattenPulseChannels(pulse.Channels)
+
+	oi["dashboard_id"] = 0
+	if pulse.DashboardId != nil {
+		oi["dashboard_id"] = *pulse.DashboardId
+	}
+	parameters, err := flattenJSON(pulse.Parameters, "[]")
+	if err != nil {
+		return nil, err
+	}
+	oi["parameters"] = parameters
+	oi["skip_if_empty"] = pulse.SkipIfEmpty
+
+	return oi, nil
+}
+
+// flattenPulseChannels returns the enabled channels and the schedule of the first one
+func flattenPulseChannels(channels []PulseChannel) map[string]interface{} {
+	oi := map[string]interface{}{
+		channelEmail: []interface{}{},
+		channelSlack: []interface{}{},
+		"schedule":   []interface{}{},
+	}
+
+	for _, channel := range channels {
+		if !channel.Enabled {
+			continue
+		}
+
+		switch channel.ChannelType {
+		case channelEmail:
+			var userIds []int
+			var addresses []string
+			for _, recipient := range channel.Recipients {
+				if recipient.Id != 0 {
+					userIds = append(userIds, recipient.Id)
+				} else {
+					addresses = append(addresses, recipient.Email)
+				}
+			}
+			sort.Ints(userIds)
+			sort.Strings(addresses)
+
+			oi[channelEmail] = []interface{}{
+				map[string]interface{}{
+					"user_ids":  userIds,
+					"addresses": addresses,
+				},
+			}
+		case channelSlack:
+			oi[channelSlack] = []interface{}{
+				map[string]interface{}{
+					"channel": fmt.Sprint(channel.Details["channel"]),
+				},
+			}
+		default:
+			continue
+		}
+
+		if len(oi["schedule"].([]interface{})) > 0 {
+			continue
+		}
+
+		schedule := map[string]interface{}{
+			"type":  channel.ScheduleType,
+			"hour":  0,
+			"day":   "",
+			"frame": "",
+		}
+		if channel.ScheduleHour != nil && channel.ScheduleType != "hourly" {
+			schedule["hour"] = *channel.ScheduleHour
+		}
+		if channel.ScheduleDay != nil && (channel.ScheduleType == "weekly" || channel.ScheduleType == "monthly") {
+			schedule["day"] = *channel.ScheduleDay
+		}
+		if channel.ScheduleFrame != nil && channel.ScheduleType == "monthly" {
+			schedule["frame"] = *channel.ScheduleFrame
+		}
+		oi["schedule"] = []interface{}{schedule}
+	}
+
+	return oi
+}
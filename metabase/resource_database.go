@@ -105,7 +105,7 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m inter
 		return diags
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/database", c.HostURL), bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/database", c.HostURL), bytes.NewBuffer(jsonBody))
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -169,7 +169,7 @@ func resourceDatabaseRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	dbId := d.Id()
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -259,7 +259,7 @@ func resourceDatabaseUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			return diags
 		}
 
-		req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), bytes.NewBuffer(jsonBody))
+		req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), bytes.NewBuffer(jsonBody))
 		if err != nil {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Error,
@@ -322,7 +322,7 @@ func resourceDatabaseDelete(ctx context.Context, d *schema.ResourceData, m inter
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/database/%s", c.HostURL, dbId), nil)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
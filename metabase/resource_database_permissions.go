@@ -0,0 +1,403 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// data access levels of Metabase and their values in the permissions graph
+const (
+	accessUnrestricted  = "unrestricted"
+	accessGranular      = "granular"
+	accessNoSelfService = "no_self_service"
+	accessBlock         = "block"
+	accessSandboxed     = "sandboxed"
+)
+
+var graphAccessValues = map[string]string{
+	accessUnrestricted:  "all",
+	accessNoSelfService: "none",
+	accessBlock:         "block",
+}
+
+func resourceDatabasePermissions() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_database_permissions` resource can be used for managing data access of a permissions group to a database.\n\n" +
+			"Only the cell of the group and the database in the permissions graph is managed, " +
+			"so other groups and databases can be managed in the UI or by other resources.",
+		CreateContext: resourceDatabasePermissionsCreate,
+		ReadContext:   resourceDatabasePermissionsRead,
+		UpdateContext: resourceDatabasePermissionsUpdate,
+		DeleteContext: resourceDatabasePermissionsDelete,
+		CustomizeDiff: resourceDatabasePermissionsCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"group_id": &schema.Schema{
+				Description:  "ID of a permissions group",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntNotInSlice([]int{administratorGroupId}),
+			},
+			"database_id": &schema.Schema{
+				Description: "ID of a database",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"access": &schema.Schema{
+				Description: "Data access of a group to a database: `unrestricted`, `granular` (configured per schema), " +
+					"`no_self_service` or `block`",
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					accessUnrestricted, accessGranular, accessNoSelfService, accessBlock,
+				}, false),
+			},
+			"native": &schema.Schema{
+				Description:  "Native query access: `write` to allow writing SQL, or `none`. Requires `unrestricted` access",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice([]string{"write", "none"}, false),
+			},
+			"schema": &schema.Schema{
+				Description: "Data access to a schema of a database, only used with `granular` access",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Description: "Name of a schema, empty for databases without schemas",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"access": &schema.Schema{
+							Description: "Data access of a group to a schema: `unrestricted`, `granular` (configured per table) or `no_self_service`",
+							Type:        schema.TypeString,
+							Required:    true,
+							ValidateFunc: validation.StringInSlice([]string{
+								accessUnrestricted, accessGranular, accessNoSelfService,
+							}, false),
+						},
+						"table": &schema.Schema{
+							Description: "Data access to a table of a schema, only used with `granular` access",
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": &schema.Schema{
+										Description: "ID of a table",
+										Type:        schema.TypeInt,
+										Required:    true,
+									},
+									"access": &schema.Schema{
+										Description: "Data access of a group to a table: `unrestricted`, `no_self_service` or `sandboxed`",
+										Type:        schema.TypeString,
+										Required:    true,
+										ValidateFunc: validation.StringInSlice([]string{
+											accessUnrestricted, accessNoSelfService, accessSandboxed,
+										}, false),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceDatabasePermissionsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	access := d.Get("access").(string)
+
+	if d.Get("native").(string) == "write" && access != accessUnrestricted {
+		return fmt.Errorf("native query access requires %q access, got %q", accessUnrestricted, access)
+	}
+
+	schemas := d.Get("schema").(*schema.Set).List()
+	if len(schemas) > 0 && access != accessGranular {
+		return fmt.Errorf("schema blocks are only used with %q access, got %q", accessGranular, access)
+	}
+	if len(schemas) == 0 && access == accessGranular {
+		return fmt.Errorf("%q access requires at least one schema block", accessGranular)
+	}
+
+	for _, s := range schemas {
+		s := s.(map[string]interface{})
+		tables := s["table"].(*schema.Set).Len()
+		if tables > 0 && s["access"].(string) != accessGranular {
+			return fmt.Errorf("table blocks of schema %q are only used with %q access", s["name"], accessGranular)
+		}
+		if tables == 0 && s["access"].(string) == accessGranular {
+			return fmt.Errorf("%q access of schema %q requires at least one table block", accessGranular, s["name"])
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabasePermissionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(fmt.Sprintf("%d:%d", d.Get("group_id").(int), d.Get("database_id").(int)))
+
+	if diags := writeDatabasePermissions(ctx, d, m, expandDataPermissions(d)); diags.HasError() {
+		d.SetId("")
+		return diags
+	}
+
+	return resourceDatabasePermissionsRead(ctx, d, m)
+}
+
+func resourceDatabasePermissionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	groupId, dbId, err := parseDatabasePermissionsId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	graph, err := readPermissionsGraph(ctx, c)
+	if err != nil {
+		return apiErrorDiags(err, "Permissions graph not found")
+	}
+
+	// Metabase leaves out databases a group has no access to
+	var data interface{}
+	if cell, ok := graph.Groups[groupId][dbId]; ok {
+		data = cell["data"]
+	}
+
+	flattened := flattenDataPermissions(data)
+	flattened["group_id"], _ = strconv.Atoi(groupId)
+	flattened["database_id"], _ = strconv.Atoi(dbId)
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign permissions attribute to d.%s in resourceDatabasePermissionsRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceDatabasePermissionsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("access", "native", "schema") {
+		if diags := writeDatabasePermissions(ctx, d, m, expandDataPermissions(d)); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceDatabasePermissionsRead(ctx, d, m)
+}
+
+func resourceDatabasePermissionsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// revokes all data access of the group to the database
+	data := map[string]interface{}{
+		"native":  "none",
+		"schemas": "none",
+	}
+
+	if diags := writeDatabasePermissions(ctx, d, m, data); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// writeDatabasePermissions submits the data permissions of one group and one
+// database along with the revision of the graph they were merged into
+func writeDatabasePermissions(ctx context.Context, d *schema.ResourceData, m interface{}, data map[string]interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	groupId, dbId, err := parseDatabasePermissionsId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.updateGraph(ctx, "/api/permissions/graph", func(current []byte) (interface{}, error) {
+		graph := &PermissionsGraph{}
+		if err := json.Unmarshal(current, graph); err != nil {
+			return nil, err
+		}
+
+		// keep download, data model and other permissions of the cell as is
+		cell := graph.Groups[groupId][dbId]
+		if cell == nil {
+			cell = make(map[string]interface{})
+		}
+		cell["data"] = data
+
+		return &PermissionsGraph{
+			Revision: graph.Revision,
+			Groups: map[string]map[string]map[string]interface{}{
+				groupId: {dbId: cell},
+			},
+		}, nil
+	})
+	if err != nil {
+		return apiErrorDiags(err, "Permissions graph not found")
+	}
+
+	return nil
+}
+
+func readPermissionsGraph(ctx context.Context, c *Client) (*PermissionsGraph, error) {
+	graph := &PermissionsGraph{}
+	if err := c.doJSON(ctx, http.MethodGet, "/api/permissions/graph", nil, graph); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+func parseDatabasePermissionsId(id string) (string, string, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("unexpected ID %q, expected <group_id>:<database_id>", id)
+	}
+
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", "", fmt.Errorf("unexpected ID %q, expected <group_id>:<database_id>", id)
+		}
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func expandDataPermissions(d *schema.ResourceData) map[string]interface{} {
+	data := map[string]interface{}{
+		"native": d.Get("native").(string),
+	}
+
+	access := d.Get("access").(string)
+	if access != accessGranular {
+		data["schemas"] = graphAccessValues[access]
+		return data
+	}
+
+	schemas := make(map[string]interface{})
+	for _, s := range d.Get("schema").(*schema.Set).List() {
+		s := s.(map[string]interface{})
+
+		if s["access"].(string) != accessGranular {
+			schemas[s["name"].(string)] = graphAccessValues[s["access"].(string)]
+			continue
+		}
+
+		tables := make(map[string]interface{})
+		for _, t := range s["table"].(*schema.Set).List() {
+			t := t.(map[string]interface{})
+			tableId := strconv.Itoa(t["id"].(int))
+
+			if t["access"].(string) == accessSandboxed {
+				tables[tableId] = map[string]interface{}{
+					"read":  "all",
+					"query": "segmented",
+				}
+				continue
+			}
+			tables[tableId] = graphAccessValues[t["access"].(string)]
+		}
+		schemas[s["name"].(string)] = tables
+	}
+	data["schemas"] = schemas
+
+	return data
+}
+
+func flattenDataPermissions(data interface{}) map[string]interface{} {
+	oi := map[string]interface{}{
+		"access": accessNoSelfService,
+		"native": "none",
+		"schema": []interface{}{},
+	}
+
+	perms, ok := data.(map[string]interface{})
+	if !ok {
+		return oi
+	}
+
+	if native, ok := perms["native"].(string); ok {
+		oi["native"] = native
+	}
+
+	switch schemas := perms["schemas"].(type) {
+	case string:
+		oi["access"] = accessFromGraph(schemas)
+	case map[string]interface{}:
+		oi["access"] = accessGranular
+
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		flattened := make([]interface{}, 0, len(schemas))
+		for _, name := range names {
+			s := map[string]interface{}{
+				"name":  name,
+				"table": []interface{}{},
+			}
+
+			switch tables := schemas[name].(type) {
+			case string:
+				s["access"] = accessFromGraph(tables)
+			case map[string]interface{}:
+				s["access"] = accessGranular
+
+				flattenedTables := make([]interface{}, 0, len(tables))
+				for tableId, table := range tables {
+					id, _ := strconv.Atoi(tableId)
+					t := map[string]interface{}{"id": id}
+
+					if access, ok := table.(string); ok {
+						t["access"] = accessFromGraph(access)
+					} else {
+						t["access"] = accessSandboxed
+					}
+					flattenedTables = append(flattenedTables, t)
+				}
+				s["table"] = flattenedTables
+			}
+
+			flattened = append(flattened, s)
+		}
+		oi["schema"] = flattened
+	}
+
+	return oi
+}
+
+func accessFromGraph(value string) string {
+	for access, v := range graphAccessValues {
+		if v == value {
+			return access
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,208 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const emailSettingsId = "email"
+
+func resourceEmailSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_email_settings` resource can be used for configuring the SMTP server Metabase sends emails with.\n\n" +
+			"Metabase tests the connection to the server before saving the settings, so an apply fails " +
+			"when the server cannot be reached or rejects the credentials. " +
+			"Destroying the resource clears the settings.",
+		CreateContext: resourceEmailSettingsWrite,
+		ReadContext:   resourceEmailSettingsRead,
+		UpdateContext: resourceEmailSettingsWrite,
+		DeleteContext: resourceEmailSettingsDelete,
+		Schema: map[string]*schema.Schema{
+			"host": &schema.Schema{
+				Description: "Host of the SMTP server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"port": &schema.Schema{
+				Description:  "Port of the SMTP server, e.g. `587`",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"security": &schema.Schema{
+				Description:  "Security of the connection: `none`, `ssl`, `tls` or `starttls`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice([]string{"none", "ssl", "tls", "starttls"}, false),
+			},
+			"username": &schema.Schema{
+				Description: "Username to log in to the SMTP server",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"password": &schema.Schema{
+				Description: "Password to log in to the SMTP server. Metabase never returns it, " +
+					"so changes made outside of Terraform are not detected",
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"from_address": &schema.Schema{
+				Description: "Email address emails are sent from",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"from_name": &schema.Schema{
+				Description: "Name emails are sent from",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"reply_to": &schema.Schema{
+				Description: "Email addresses replies are sent to",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceEmailSettingsWrite(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodPut, "/api/email", expandEmailSettings(d), nil)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusBadRequest {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "SMTP connection test failed",
+			Detail: fmt.Sprintf("Metabase could not connect to %s:%d with the given settings: %s",
+				d.Get("host").(string), d.Get("port").(int), apiErr.Message),
+		})
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, "Email settings not found")
+	}
+
+	d.SetId(emailSettingsId)
+
+	return resourceEmailSettingsRead(ctx, d, m)
+}
+
+func resourceEmailSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	values, err := readSettingValues(ctx, c,
+		"email-smtp-host",
+		"email-smtp-port",
+		"email-smtp-security",
+		"email-smtp-username",
+		"email-from-address",
+		"email-from-name",
+		"email-reply-to",
+	)
+	if err != nil {
+		return apiErrorDiags(err, "Email settings not found")
+	}
+
+	for k, v := range flattenEmailSettings(values) {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign email setting to d.%s in resourceEmailSettingsRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceEmailSettingsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodDelete, "/api/email", nil, nil)
+	if err != nil {
+		return apiErrorDiags(err, "Email settings not found")
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandEmailSettings(d *schema.ResourceData) map[string]interface{} {
+	settings := map[string]interface{}{
+		"email-smtp-host":     d.Get("host").(string),
+		"email-smtp-port":     d.Get("port").(int),
+		"email-smtp-security": d.Get("security").(string),
+		"email-smtp-username": nil,
+		"email-smtp-password": nil,
+		"email-from-address":  d.Get("from_address").(string),
+		"email-from-name":     nil,
+	}
+
+	if v, ok := d.GetOk("username"); ok {
+		settings["email-smtp-username"] = v.(string)
+	}
+
+	if v, ok := d.GetOk("password"); ok {
+		settings["email-smtp-password"] = v.(string)
+	}
+
+	if v, ok := d.GetOk("from_name"); ok {
+		settings["email-from-name"] = v.(string)
+	}
+
+	// only sent when configured, older Metabase versions do not know it
+	if v, ok := d.GetOk("reply_to"); ok {
+		settings["email-reply-to"] = v.([]interface{})
+	} else if d.HasChange("reply_to") {
+		settings["email-reply-to"] = nil
+	}
+
+	return settings
+}
+
+func flattenEmailSettings(values map[string]interface{}) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	oi["host"] = settingStringValue(values["email-smtp-host"])
+	oi["port"] = settingIntValue(values["email-smtp-port"])
+	oi["security"] = settingStringValue(values["email-smtp-security"])
+	if oi["security"] == "" {
+		oi["security"] = "none"
+	}
+	oi["username"] = settingStringValue(values["email-smtp-username"])
+	oi["from_address"] = settingStringValue(values["email-from-address"])
+	oi["from_name"] = settingStringValue(values["email-from-name"])
+
+	replyTo := []interface{}{}
+	if addresses, ok := values["email-reply-to"].([]interface{}); ok {
+		replyTo = addresses
+	}
+	oi["reply_to"] = replyTo
+
+	return oi
+}
@@ -0,0 +1,516 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const semanticTypeFK = "type/FK"
+
+func resourceField() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_field` resource can be used for managing the metadata of a field synced by Metabase.\n\n" +
+			"The field is adopted by its database, schema, table and name, it is never created or deleted: " +
+			"destroying the resource only removes it from the state and keeps the metadata as is.",
+		CreateContext: resourceFieldCreate,
+		ReadContext:   resourceFieldRead,
+		UpdateContext: resourceFieldUpdate,
+		DeleteContext: resourceFieldDelete,
+		CustomizeDiff: resourceFieldCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"database_id": &schema.Schema{
+				Description: "ID of the database of a field",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"schema": &schema.Schema{
+				Description: "Schema of the table of a field. Can be omitted when the table name is unique in the database",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"table": &schema.Schema{
+				Description: "Name of the table of a field",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Name of a field in the database",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"table_id": &schema.Schema{
+				Description: "ID of the table of a field",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"display_name": &schema.Schema{
+				Description: "Name of a field displayed by Metabase, generated from the name when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Description of a field",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"semantic_type": &schema.Schema{
+				Description: "Semantic type of a field, e.g. `type/PK`, `type/FK`, `type/Email` or `type/Category`, " +
+					"detected by Metabase when not set. Removing it from the configuration clears it",
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"fk_target_field_id": &schema.Schema{
+				Description: "ID of the field a foreign key points to, requires `type/FK` semantic type. " +
+					"Removing it from the configuration clears it",
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"has_field_values": &schema.Schema{
+				Description:  "How filters on a field pick values: `list`, `search`, `none` or `auto-list`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"list", "search", "none", "auto-list"}, false),
+			},
+			"visibility_type": &schema.Schema{
+				Description:  "Visibility of a field: `normal`, `details-only`, `sensitive` or `retired`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "normal",
+				ValidateFunc: validation.StringInSlice([]string{"normal", "details-only", "sensitive", "retired"}, false),
+			},
+			"remapping": &schema.Schema{
+				Description: "Display values of a field, either custom `values` or another field of the table " +
+					"a foreign key points to with `field_id`",
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Description: "Name of the remapped column, the display name of a field when not set",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+						},
+						"field_id": &schema.Schema{
+							Description: "ID of a field of the foreign key target table to display instead of the foreign key",
+							Type:        schema.TypeInt,
+							Optional:    true,
+						},
+						"values": &schema.Schema{
+							Description: "Display values by original value, requires `list` field values",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceFieldCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config := d.GetRawConfig()
+
+	if !config.IsNull() && config.IsKnown() && !config.GetAttr("fk_target_field_id").IsNull() &&
+		d.NewValueKnown("semantic_type") &&
+		d.Get("semantic_type").(string) != semanticTypeFK {
+		return fmt.Errorf("fk_target_field_id requires %q semantic type, got %q", semanticTypeFK, d.Get("semantic_type").(string))
+	}
+
+	remappings := d.Get("remapping").([]interface{})
+	if len(remappings) == 0 || remappings[0] == nil {
+		return nil
+	}
+	remapping := remappings[0].(map[string]interface{})
+
+	hasField := remapping["field_id"].(int) != 0
+	hasValues := len(remapping["values"].(map[string]interface{})) > 0
+	if hasField == hasValues && d.NewValueKnown("remapping") {
+		return fmt.Errorf("remapping requires exactly one of field_id and values")
+	}
+
+	return nil
+}
+
+func resourceFieldCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	table, diags := findTable(ctx, c, d.Get("database_id").(int), d.Get("schema").(string), d.Get("table").(string))
+	if diags.HasError() {
+		return diags
+	}
+
+	field, diags := findField(ctx, c, table, d.Get("name").(string))
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Info(ctx, "Adopting field", map[string]interface{}{
+		"id":    field.Id,
+		"table": table.Name,
+		"name":  field.Name,
+	})
+	d.SetId(strconv.Itoa(field.Id))
+
+	// adopted fields get exactly the configured remapping
+	dimension, err := fieldDimension(field)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to decode field dimensions in resourceFieldCreate()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	if diags := writeField(ctx, c, d, field, dimension != nil); diags.HasError() {
+		return diags
+	}
+
+	return resourceFieldRead(ctx, d, m)
+}
+
+func resourceFieldRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	field, err := readField(ctx, c, d.Id())
+	if isNotFound(err) || (err == nil && !field.Active) {
+		tflog.Warn(ctx, "Field is deleted or no longer synced, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Field %s not found", d.Id()))
+	}
+
+	table := &Table{}
+	err = c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/table/%d", field.TableId), nil, table)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Table %d not found", field.TableId))
+	}
+
+	dimension, err := fieldDimension(field)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to decode field dimensions in resourceFieldRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	var values *FieldValues
+	if dimension != nil && dimension.Type == "internal" {
+		values = &FieldValues{}
+		err = c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/field/%s/values", d.Id()), nil, values)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Field %s not found", d.Id()))
+		}
+	}
+
+	flattened := flattenField(field, table)
+	flattened["remapping"] = flattenFieldRemapping(dimension, values)
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign field attribute to d.%s in resourceFieldRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceFieldUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("display_name", "description", "semantic_type", "fk_target_field_id", "has_field_values",
+		"visibility_type", "remapping") {
+		field, err := readField(ctx, c, d.Id())
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Field %s not found", d.Id()))
+		}
+
+		old, _ := d.GetChange("remapping")
+		if diags := writeField(ctx, c, d, field, len(old.([]interface{})) > 0); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceFieldRead(ctx, d, m)
+}
+
+func resourceFieldDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// fields belong to their table, Metabase only retires them on sync
+	tflog.Info(ctx, "Removing field from state, its metadata is kept in Metabase", map[string]interface{}{
+		"id": d.Id(),
+	})
+	d.SetId("")
+
+	return diags
+}
+
+func readField(ctx context.Context, c *Client, id string) (*Field, error) {
+	field := &Field{}
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/field/%s", id), nil, field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// findField returns the field of a table with the name
+func findField(ctx context.Context, c *Client, table *Table, name string) (*Field, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	metadata := &Table{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/table/%d/query_metadata", table.Id), nil, metadata)
+	if err != nil {
+		return nil, apiErrorDiags(err, fmt.Sprintf("Table %d not found", table.Id))
+	}
+
+	for _, field := range metadata.Fields {
+		if field.Name == name {
+			return &field, diags
+		}
+	}
+
+	diags = append(diags, diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "Field not found",
+		Detail: fmt.Sprintf("No field with name %q found in table %q (ID %d), make sure the database is synced",
+			name, table.Name, table.Id),
+	})
+	return nil, diags
+}
+
+// writeField updates the metadata of a field, then replaces its remapping.
+// An existing remapping is removed when none is configured.
+func writeField(ctx context.Context, c *Client, d *schema.ResourceData, field *Field, hasRemapping bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/field/%s", d.Id()), expandField(d), nil)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Field %s not found", d.Id()))
+	}
+
+	remappings := d.Get("remapping").([]interface{})
+	if len(remappings) == 0 || remappings[0] == nil {
+		if hasRemapping {
+			err = c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/field/%s/dimension", d.Id()), nil, nil)
+			if err != nil && !isNotFound(err) {
+				return apiErrorDiags(err, fmt.Sprintf("Field %s not found", d.Id()))
+			}
+		}
+		return diags
+	}
+	remapping := remappings[0].(map[string]interface{})
+
+	dimension := &FieldDimension{
+		Type: "internal",
+		Name: remapping["name"].(string),
+	}
+	if dimension.Name == "" {
+		dimension.Name = d.Get("display_name").(string)
+	}
+	if dimension.Name == "" {
+		dimension.Name = field.DisplayName
+	}
+	if fieldId := remapping["field_id"].(int); fieldId != 0 {
+		dimension.Type = "external"
+		dimension.HumanReadableFieldId = &fieldId
+	}
+
+	err = c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/api/field/%s/dimension", d.Id()), dimension, nil)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Field %s or the remapping field not found", d.Id()))
+	}
+
+	if dimension.Type == "internal" {
+		values := expandFieldValues(remapping["values"].(map[string]interface{}), field.BaseType)
+		err = c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/api/field/%s/values", d.Id()), values, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Field %s not found", d.Id()))
+		}
+	}
+
+	return diags
+}
+
+func expandField(d *schema.ResourceData) *FieldUpdate {
+	field := &FieldUpdate{
+		DisplayName:    d.Get("display_name").(string),
+		HasFieldValues: d.Get("has_field_values").(string),
+		VisibilityType: d.Get("visibility_type").(string),
+	}
+
+	if v, ok := d.GetOk("description"); ok {
+		s := v.(string)
+		field.Description = &s
+	}
+
+	field.SemanticType = expandFieldAttribute(d, "semantic_type")
+	field.FkTargetFieldId = expandFieldAttribute(d, "fk_target_field_id")
+
+	return field
+}
+
+// expandFieldAttribute returns the configured value of a computed attribute.
+// Unset attributes are omitted so Metabase keeps the detected value, unless
+// they were removed from the configuration: those are sent as null to clear them.
+func expandFieldAttribute(d *schema.ResourceData, key string) json.RawMessage {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	if config.GetAttr(key).IsNull() {
+		old, _ := d.GetChange(key)
+		if old != nil && old != "" && old != 0 {
+			return json.RawMessage("null")
+		}
+		return nil
+	}
+
+	value, err := json.Marshal(d.Get(key))
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// expandFieldValues returns remapped values, numeric fields are sent numbers
+// so they match the values stored by Metabase
+func expandFieldValues(remapped map[string]interface{}, baseType string) *FieldValues {
+	numeric := false
+	for _, t := range []string{"Integer", "Float", "Decimal", "Number"} {
+		if strings.Contains(baseType, t) {
+			numeric = true
+		}
+	}
+
+	keys := make([]string, 0, len(remapped))
+	for k := range remapped {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := &FieldValues{Values: [][]interface{}{}}
+	for _, k := range keys {
+		var value interface{} = k
+		if _, err := strconv.ParseFloat(k, 64); err == nil && numeric {
+			value = json.Number(k)
+		}
+		values.Values = append(values.Values, []interface{}{value, remapped[k]})
+	}
+
+	return values
+}
+
+// fieldDimension returns the remapping of a field, Metabase returns
+// a list of dimensions or a single one depending on the version
+func fieldDimension(field *Field) (*FieldDimension, error) {
+	if len(field.Dimensions) == 0 || string(field.Dimensions) == "null" {
+		return nil, nil
+	}
+
+	var dimensions []FieldDimension
+	if err := json.Unmarshal(field.Dimensions, &dimensions); err != nil {
+		dimension := &FieldDimension{}
+		if err := json.Unmarshal(field.Dimensions, dimension); err != nil {
+			return nil, err
+		}
+		dimensions = []FieldDimension{*dimension}
+	}
+
+	for _, dimension := range dimensions {
+		if dimension.Type != "" {
+			return &dimension, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func flattenField(field *Field, table *Table) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	oi["database_id"] = table.DbId
+	oi["schema"] = table.Schema
+	oi["table"] = table.Name
+	oi["table_id"] = field.TableId
+	oi["name"] = field.Name
+	oi["display_name"] = field.DisplayName
+	oi["description"] = field.Description
+	oi["semantic_type"] = field.SemanticType
+	oi["fk_target_field_id"] = field.FkTargetFieldId
+	oi["has_field_values"] = field.HasFieldValues
+	oi["visibility_type"] = field.VisibilityType
+
+	return oi
+}
+
+func flattenFieldRemapping(dimension *FieldDimension, values *FieldValues) []interface{} {
+	if dimension == nil {
+		return []interface{}{}
+	}
+
+	remapping := map[string]interface{}{
+		"name":     dimension.Name,
+		"field_id": 0,
+		"values":   map[string]interface{}{},
+	}
+
+	if dimension.HumanReadableFieldId != nil {
+		remapping["field_id"] = *dimension.HumanReadableFieldId
+	}
+
+	if values != nil {
+		remapped := make(map[string]interface{})
+		for _, value := range values.Values {
+			if len(value) > 1 && value[1] != nil {
+				remapped[fmt.Sprint(value[0])] = fmt.Sprint(value[1])
+			}
+		}
+		remapping["values"] = remapped
+	}
+
+	return []interface{}{remapping}
+}
@@ -0,0 +1,330 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const ldapSettingsId = "ldap"
+
+func resourceLdapSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_ldap_settings` resource can be used for configuring LDAP authentication.\n\n" +
+			"Metabase tests the connection to the server before saving enabled settings, so an apply fails " +
+			"when the server cannot be reached or rejects the bind credentials. " +
+			"Destroying the resource disables LDAP authentication and clears the settings.",
+		CreateContext: resourceLdapSettingsWrite,
+		ReadContext:   resourceLdapSettingsRead,
+		UpdateContext: resourceLdapSettingsWrite,
+		DeleteContext: resourceLdapSettingsDelete,
+		Schema: map[string]*schema.Schema{
+			"enabled": &schema.Schema{
+				Description: "Whether users can log in with LDAP",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"host": &schema.Schema{
+				Description: "Host of the LDAP server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"port": &schema.Schema{
+				Description:  "Port of the LDAP server",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      389,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"security": &schema.Schema{
+				Description:  "Security of the connection: `none`, `ssl` or `starttls`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice([]string{"none", "ssl", "starttls"}, false),
+			},
+			"bind_dn": &schema.Schema{
+				Description: "Distinguished name to bind as to look up users, anonymous when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"password": &schema.Schema{
+				Description: "Password of the bind user. Metabase never returns it, " +
+					"so changes made outside of Terraform are not detected",
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"user_base": &schema.Schema{
+				Description: "Search base for users, e.g. `ou=users,dc=example,dc=org`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"user_filter": &schema.Schema{
+				Description: "Filter to look up users by their login, which replaces `{login}`. " +
+					"Metabase matches `uid` and `mail` of `inetOrgPerson` entries when not set",
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"attribute_email": &schema.Schema{
+				Description: "Attribute holding the email of a user, `mail` when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"attribute_firstname": &schema.Schema{
+				Description: "Attribute holding the first name of a user, `givenName` when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"attribute_lastname": &schema.Schema{
+				Description: "Attribute holding the last name of a user, `sn` when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"group_sync": &schema.Schema{
+				Description: "Whether to sync group memberships of users from LDAP on login",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"group_base": &schema.Schema{
+				Description: "Search base for groups, needed for directories without the `memberOf` overlay",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"group_mapping": &schema.Schema{
+				Description: "Mapping of an LDAP group to Metabase permissions groups, used with `group_sync`",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": &schema.Schema{
+							Description: "Distinguished name of an LDAP group",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"group_ids": &schema.Schema{
+							Description: "IDs of the permissions groups members of the LDAP group are added to",
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceLdapSettingsWrite(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	settings, err := expandLdapSettings(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid LDAP group mappings",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	err = c.doJSON(ctx, http.MethodPut, "/api/ldap/settings", settings, nil)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusBadRequest {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "LDAP connection test failed",
+			Detail: fmt.Sprintf("Metabase could not connect to %s:%d with the given settings: %s",
+				d.Get("host").(string), d.Get("port").(int), apiErr.Message),
+		})
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, "LDAP settings not found")
+	}
+
+	d.SetId(ldapSettingsId)
+
+	return resourceLdapSettingsRead(ctx, d, m)
+}
+
+func resourceLdapSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	values, err := readSettingValues(ctx, c,
+		"ldap-enabled",
+		"ldap-host",
+		"ldap-port",
+		"ldap-security",
+		"ldap-bind-dn",
+		"ldap-user-base",
+		"ldap-user-filter",
+		"ldap-attribute-email",
+		"ldap-attribute-firstname",
+		"ldap-attribute-lastname",
+		"ldap-group-sync",
+		"ldap-group-base",
+		"ldap-group-mappings",
+	)
+	if err != nil {
+		return apiErrorDiags(err, "LDAP settings not found")
+	}
+
+	for k, v := range flattenLdapSettings(values) {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign LDAP setting to d.%s in resourceLdapSettingsRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceLdapSettingsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// disabled settings are saved without a connection test
+	err := c.doJSON(ctx, http.MethodPut, "/api/ldap/settings", map[string]interface{}{
+		"ldap-enabled":             false,
+		"ldap-host":                nil,
+		"ldap-port":                nil,
+		"ldap-security":            nil,
+		"ldap-bind-dn":             nil,
+		"ldap-password":            nil,
+		"ldap-user-base":           nil,
+		"ldap-user-filter":         nil,
+		"ldap-attribute-email":     nil,
+		"ldap-attribute-firstname": nil,
+		"ldap-attribute-lastname":  nil,
+		"ldap-group-sync":          false,
+		"ldap-group-base":          nil,
+		"ldap-group-mappings":      map[string]interface{}{},
+	}, nil)
+	if err != nil {
+		return apiErrorDiags(err, "LDAP settings not found")
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandLdapSettings(d *schema.ResourceData) (map[string]interface{}, error) {
+	settings := map[string]interface{}{
+		"ldap-enabled":    d.Get("enabled").(bool),
+		"ldap-host":       d.Get("host").(string),
+		"ldap-port":       d.Get("port").(int),
+		"ldap-security":   d.Get("security").(string),
+		"ldap-bind-dn":    nil,
+		"ldap-password":   nil,
+		"ldap-user-base":  d.Get("user_base").(string),
+		"ldap-group-sync": d.Get("group_sync").(bool),
+		"ldap-group-base": nil,
+	}
+
+	optional := map[string]string{
+		"bind_dn":             "ldap-bind-dn",
+		"password":            "ldap-password",
+		"user_filter":         "ldap-user-filter",
+		"attribute_email":     "ldap-attribute-email",
+		"attribute_firstname": "ldap-attribute-firstname",
+		"attribute_lastname":  "ldap-attribute-lastname",
+		"group_base":          "ldap-group-base",
+	}
+	for attribute, key := range optional {
+		if v, ok := d.GetOk(attribute); ok {
+			settings[key] = v.(string)
+		}
+	}
+
+	mappings := make(map[string][]int)
+	for _, v := range d.Get("group_mapping").(*schema.Set).List() {
+		mapping := v.(map[string]interface{})
+
+		dn := mapping["dn"].(string)
+		if _, ok := mappings[dn]; ok {
+			return nil, fmt.Errorf("LDAP group %q is mapped more than once", dn)
+		}
+
+		ids := []int{}
+		for _, id := range mapping["group_ids"].(*schema.Set).List() {
+			ids = append(ids, id.(int))
+		}
+		sort.Ints(ids)
+		mappings[dn] = ids
+	}
+	settings["ldap-group-mappings"] = mappings
+
+	return settings, nil
+}
+
+func flattenLdapSettings(values map[string]interface{}) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	enabled, _ := values["ldap-enabled"].(bool)
+	groupSync, _ := values["ldap-group-sync"].(bool)
+
+	oi["enabled"] = enabled
+	oi["host"] = settingStringValue(values["ldap-host"])
+	oi["port"] = settingIntValue(values["ldap-port"])
+	oi["security"] = settingStringValue(values["ldap-security"])
+	if oi["security"] == "" {
+		oi["security"] = "none"
+	}
+	oi["bind_dn"] = settingStringValue(values["ldap-bind-dn"])
+	oi["user_base"] = settingStringValue(values["ldap-user-base"])
+	oi["user_filter"] = settingStringValue(values["ldap-user-filter"])
+	oi["attribute_email"] = settingStringValue(values["ldap-attribute-email"])
+	oi["attribute_firstname"] = settingStringValue(values["ldap-attribute-firstname"])
+	oi["attribute_lastname"] = settingStringValue(values["ldap-attribute-lastname"])
+	oi["group_sync"] = groupSync
+	oi["group_base"] = settingStringValue(values["ldap-group-base"])
+
+	mappings := []interface{}{}
+	if groups, ok := values["ldap-group-mappings"].(map[string]interface{}); ok {
+		for dn, ids := range groups {
+			groupIds := []interface{}{}
+			if list, ok := ids.([]interface{}); ok {
+				for _, id := range list {
+					groupIds = append(groupIds, settingIntValue(id))
+				}
+			}
+			mappings = append(mappings, map[string]interface{}{
+				"dn":        dn,
+				"group_ids": groupIds,
+			})
+		}
+	}
+	oi["group_mapping"] = mappings
+
+	return oi
+}
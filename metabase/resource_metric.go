@@ -0,0 +1,44 @@
+package metabase
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMetric() *schema.Resource {
+	s := tableDefinitionSchema("metric")
+	s["definition"].Description = "MBQL definition of a metric as JSON, " +
+		"e.g. `{\"source-table\": 2, \"aggregation\": [[\"count\"]]}`"
+
+	return &schema.Resource{
+		Description: "`metabase_metric` resource can be used for managing legacy metrics, named aggregations of a table.\n\n" +
+			"Legacy metrics were removed in Metabase 50, use `metabase_card` with type `metric` there. " +
+			"Destroying the resource archives the metric.",
+		CreateContext: resourceMetricCreate,
+		ReadContext:   resourceMetricRead,
+		UpdateContext: resourceMetricUpdate,
+		DeleteContext: resourceMetricDelete,
+		Schema:        s,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceMetricCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return createTableDefinition(ctx, d, m, "/api/metric", "Metric")
+}
+
+func resourceMetricRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readTableDefinition(ctx, d, m, "/api/metric", "Metric")
+}
+
+func resourceMetricUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return updateTableDefinition(ctx, d, m, "/api/metric", "Metric")
+}
+
+func resourceMetricDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteTableDefinition(ctx, d, m, "/api/metric", "Metric")
+}
@@ -0,0 +1,191 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// builtinGroupNames are managed by Metabase itself and can't be changed
+var builtinGroupNames = []string{"All Users", "Administrators"}
+
+func resourcePermissionsGroup() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_permissions_group` resource can be used for managing permissions groups.\n\n" +
+			"The built-in \"All Users\" and \"Administrators\" groups can't be managed, " +
+			"use the `metabase_permissions_group` data source to reference them.",
+		CreateContext: resourcePermissionsGroupCreate,
+		ReadContext:   resourcePermissionsGroupRead,
+		UpdateContext: resourcePermissionsGroupUpdate,
+		DeleteContext: resourcePermissionsGroupDelete,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Description:  "Name of a group, unique in Metabase",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringNotInSlice(builtinGroupNames, true),
+			},
+			"member_count": &schema.Schema{
+				Description: "Number of users in a group",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePermissionsGroupImport,
+		},
+	}
+}
+
+func resourcePermissionsGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	group := &PermissionsGroup{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/permissions/group", &PermissionsGroup{Name: d.Get("name").(string)}, group)
+	if err != nil {
+		return apiErrorDiags(err, "Unable to create permissions group")
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+
+	return resourcePermissionsGroupRead(ctx, d, m)
+}
+
+func resourcePermissionsGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	group, err := findPermissionsGroup(ctx, c, func(g PermissionsGroup) bool {
+		return strconv.Itoa(g.Id) == d.Id()
+	})
+	if err != nil {
+		return apiErrorDiags(err, "Permissions groups not found")
+	}
+	if group == nil {
+		tflog.Warn(ctx, "Permissions group is deleted, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("name", group.Name); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign group.Name to d.name in resourcePermissionsGroupRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	if err := d.Set("member_count", group.MemberCount); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign group.MemberCount to d.member_count in resourcePermissionsGroupRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	return diags
+}
+
+func resourcePermissionsGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if diags := checkNotBuiltinGroup(d.Id()); diags.HasError() {
+		return diags
+	}
+
+	if d.HasChange("name") {
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/permissions/group/%s", d.Id()), &PermissionsGroup{Name: d.Get("name").(string)}, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Permissions group %s not found", d.Id()))
+		}
+	}
+
+	return resourcePermissionsGroupRead(ctx, d, m)
+}
+
+func resourcePermissionsGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	if diags := checkNotBuiltinGroup(d.Id()); diags.HasError() {
+		return diags
+	}
+
+	err := c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/permissions/group/%s", d.Id()), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourcePermissionsGroupImport accepts either ID or name of a group
+func resourcePermissionsGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*Client)
+
+	group, err := findPermissionsGroup(ctx, c, func(g PermissionsGroup) bool {
+		return strconv.Itoa(g.Id) == d.Id() || g.Name == d.Id()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("permissions group %q not found", d.Id())
+	}
+
+	if diags := checkNotBuiltinGroup(strconv.Itoa(group.Id)); diags.HasError() {
+		return nil, fmt.Errorf("%s: %s", diags[0].Summary, diags[0].Detail)
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// findPermissionsGroup returns the first group matching the filter,
+// or nil when there is no such group
+func findPermissionsGroup(ctx context.Context, c *Client, filter func(PermissionsGroup) bool) (*PermissionsGroup, error) {
+	var groups []PermissionsGroup
+	if err := c.getList(ctx, "/api/permissions/group", &groups); err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if filter(group) {
+			return &group, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func checkNotBuiltinGroup(id string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if id == strconv.Itoa(allUsersGroupId) || id == strconv.Itoa(administratorGroupId) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Built-in permissions group",
+			Detail: fmt.Sprintf("Permissions group %s is built into Metabase and can't be managed, "+
+				"use the metabase_permissions_group data source to reference it", id),
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,204 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourcePermissionsGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_permissions_group_members` resource can be used for managing the full list of members of a permissions group.\n\n" +
+			"It is authoritative: users added to the group outside of Terraform are removed from it. " +
+			"Don't combine it with `metabase_permissions_group_membership` for the same group, " +
+			"and don't set `group_ids` of a `metabase_user` managed by this resource.",
+		CreateContext: resourcePermissionsGroupMembersCreate,
+		ReadContext:   resourcePermissionsGroupMembersRead,
+		UpdateContext: resourcePermissionsGroupMembersUpdate,
+		DeleteContext: resourcePermissionsGroupMembersDelete,
+		Schema: map[string]*schema.Schema{
+			"group_id": &schema.Schema{
+				Description:  "ID of a group, except the built-in \"All Users\" and \"Administrators\"",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntNotInSlice([]int{allUsersGroupId, administratorGroupId}),
+			},
+			"user_ids": &schema.Schema{
+				Description: "IDs of all users of a group",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePermissionsGroupMembersImport,
+		},
+	}
+}
+
+func resourcePermissionsGroupMembersCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(strconv.Itoa(d.Get("group_id").(int)))
+
+	if diags := syncGroupMembers(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourcePermissionsGroupMembersRead(ctx, d, m)
+}
+
+func resourcePermissionsGroupMembersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	groupId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	members, err := readGroupMembers(ctx, c, groupId)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Permissions group is deleted, removing its members from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %d not found", groupId))
+	}
+
+	userIds := make([]int, 0, len(members))
+	for _, member := range members {
+		userIds = append(userIds, member.UserId)
+	}
+	sort.Ints(userIds)
+
+	if err := d.Set("group_id", groupId); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign group ID to d.group_id in resourcePermissionsGroupMembersRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	if err := d.Set("user_ids", userIds); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign members to d.user_ids in resourcePermissionsGroupMembersRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	return diags
+}
+
+func resourcePermissionsGroupMembersUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("user_ids") {
+		if diags := syncGroupMembers(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourcePermissionsGroupMembersRead(ctx, d, m)
+}
+
+func resourcePermissionsGroupMembersDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	groupId := d.Get("group_id").(int)
+
+	members, err := readGroupMembers(ctx, c, groupId)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %d not found", groupId))
+	}
+
+	for _, member := range members {
+		if err := removeGroupMember(ctx, c, member.MembershipId); err != nil && !isNotFound(err) {
+			return apiErrorDiags(err, fmt.Sprintf("Permissions membership %d not found", member.MembershipId))
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourcePermissionsGroupMembersImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	groupId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("unexpected ID %q, expected ID of a permissions group", d.Id())
+	}
+
+	if groupId == allUsersGroupId || groupId == administratorGroupId {
+		return nil, fmt.Errorf("members of the built-in permissions group %d can't be managed", groupId)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// syncGroupMembers adds configured users missing in a group and removes
+// all other members, including the ones added in the UI
+func syncGroupMembers(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	groupId := d.Get("group_id").(int)
+
+	members, err := readGroupMembers(ctx, c, groupId)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %d not found", groupId))
+	}
+
+	wanted := make(map[int]bool)
+	for _, id := range d.Get("user_ids").(*schema.Set).List() {
+		wanted[id.(int)] = true
+	}
+
+	current := make(map[int]bool)
+	for _, member := range members {
+		current[member.UserId] = true
+
+		if wanted[member.UserId] {
+			continue
+		}
+
+		tflog.Debug(ctx, "Removing user from permissions group", map[string]interface{}{
+			"group_id": groupId,
+			"user_id":  member.UserId,
+		})
+		if err := removeGroupMember(ctx, c, member.MembershipId); err != nil && !isNotFound(err) {
+			return apiErrorDiags(err, fmt.Sprintf("Permissions membership %d not found", member.MembershipId))
+		}
+	}
+
+	for userId := range wanted {
+		if current[userId] {
+			continue
+		}
+
+		tflog.Debug(ctx, "Adding user to permissions group", map[string]interface{}{
+			"group_id": groupId,
+			"user_id":  userId,
+		})
+		if err := addGroupMember(ctx, c, groupId, userId); err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Permissions group %d or user %d not found", groupId, userId))
+		}
+	}
+
+	return nil
+}
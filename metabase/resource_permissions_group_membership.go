@@ -0,0 +1,210 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourcePermissionsGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_permissions_group_membership` resource can be used for adding a single user to a permissions group.\n\n" +
+			"It is additive: other members of the group are left untouched. " +
+			"Don't set `group_ids` of a `metabase_user` managed by this resource.",
+		CreateContext: resourcePermissionsGroupMembershipCreate,
+		ReadContext:   resourcePermissionsGroupMembershipRead,
+		DeleteContext: resourcePermissionsGroupMembershipDelete,
+		Schema: map[string]*schema.Schema{
+			"group_id": &schema.Schema{
+				Description:  "ID of a group, except the built-in \"All Users\" and \"Administrators\"",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntNotInSlice([]int{allUsersGroupId, administratorGroupId}),
+			},
+			"user_id": &schema.Schema{
+				Description: "ID of a user",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"membership_id": &schema.Schema{
+				Description: "ID of the membership in Metabase",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePermissionsGroupMembershipImport,
+		},
+	}
+}
+
+func resourcePermissionsGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	groupId := d.Get("group_id").(int)
+	userId := d.Get("user_id").(int)
+
+	members, err := readGroupMembers(ctx, c, groupId)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %d not found", groupId))
+	}
+
+	// adding a member twice fails, so adopt a membership made in the UI
+	if findGroupMember(members, userId) == nil {
+		if err := addGroupMember(ctx, c, groupId, userId); err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Permissions group %d or user %d not found", groupId, userId))
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%d:%d", groupId, userId))
+
+	return resourcePermissionsGroupMembershipRead(ctx, d, m)
+}
+
+func resourcePermissionsGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	groupId, userId, err := parseGroupMembershipId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	members, err := readGroupMembers(ctx, c, groupId)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %d not found", groupId))
+	}
+
+	member := findGroupMember(members, userId)
+	if member == nil {
+		tflog.Warn(ctx, "Permissions group membership is deleted, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+
+	for k, v := range map[string]interface{}{
+		"group_id":      groupId,
+		"user_id":       userId,
+		"membership_id": member.MembershipId,
+	} {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign membership attribute to d.%s in resourcePermissionsGroupMembershipRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourcePermissionsGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	groupId, userId, err := parseGroupMembershipId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the membership ID changes when a user is removed and added again outside of Terraform
+	members, err := readGroupMembers(ctx, c, groupId)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("Permissions group %d not found", groupId))
+	}
+
+	if member := findGroupMember(members, userId); member != nil {
+		if err := removeGroupMember(ctx, c, member.MembershipId); err != nil && !isNotFound(err) {
+			return apiErrorDiags(err, fmt.Sprintf("Permissions membership %d not found", member.MembershipId))
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourcePermissionsGroupMembershipImport accepts IDs in <group_id>:<user_id> format
+func resourcePermissionsGroupMembershipImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	groupId, _, err := parseGroupMembershipId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if groupId == allUsersGroupId || groupId == administratorGroupId {
+		return nil, fmt.Errorf("memberships of the built-in permissions group %d can't be managed", groupId)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseGroupMembershipId(id string) (int, int, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected ID %q, expected <group_id>:<user_id>", id)
+	}
+
+	groupId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected group ID in %q: %s", id, err)
+	}
+
+	userId, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected user ID in %q: %s", id, err)
+	}
+
+	return groupId, userId, nil
+}
+
+// readGroupMembers returns current memberships of a group
+func readGroupMembers(ctx context.Context, c *Client, groupId int) ([]PermissionsMembership, error) {
+	group := &PermissionsGroup{}
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/permissions/group/%d", groupId), nil, group); err != nil {
+		return nil, err
+	}
+
+	for i := range group.Members {
+		group.Members[i].GroupId = groupId
+	}
+
+	return group.Members, nil
+}
+
+func findGroupMember(members []PermissionsMembership, userId int) *PermissionsMembership {
+	for _, member := range members {
+		if member.UserId == userId {
+			return &member
+		}
+	}
+
+	return nil
+}
+
+func addGroupMember(ctx context.Context, c *Client, groupId, userId int) error {
+	return c.doJSON(ctx, http.MethodPost, "/api/permissions/membership", &PermissionsMembership{
+		GroupId: groupId,
+		UserId:  userId,
+	}, nil)
+}
+
+func removeGroupMember(ctx context.Context, c *Client, membershipId int) error {
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/permissions/membership/%d", membershipId), nil, nil)
+}
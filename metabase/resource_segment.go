@@ -0,0 +1,217 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const defaultRevisionMessage = "Updated by Terraform"
+
+func resourceSegment() *schema.Resource {
+	s := tableDefinitionSchema("segment")
+	s["definition"].Description = "MBQL definition of a segment as JSON, " +
+		"e.g. `{\"source-table\": 2, \"filter\": [\">\", [\"field\", 10, null], 0]}`"
+
+	return &schema.Resource{
+		Description: "`metabase_segment` resource can be used for managing segments, named filters of a table.\n\n" +
+			"Destroying the resource archives the segment.",
+		CreateContext: resourceSegmentCreate,
+		ReadContext:   resourceSegmentRead,
+		UpdateContext: resourceSegmentUpdate,
+		DeleteContext: resourceSegmentDelete,
+		Schema:        s,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+// tableDefinitionSchema is shared by segments and legacy metrics,
+// named MBQL definitions of a table with the same API
+func tableDefinitionSchema(kind string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"table_id": &schema.Schema{
+			Description: "ID of a table",
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+		},
+		"name": &schema.Schema{
+			Description: fmt.Sprintf("Name of a %s", kind),
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+		"description": &schema.Schema{
+			Description: fmt.Sprintf("Description of a %s", kind),
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"definition": &schema.Schema{
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			StateFunc:        jsonStateFunc,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+		"revision_message": &schema.Schema{
+			Description: "Message recorded in the revision history on updates and archiving",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     defaultRevisionMessage,
+		},
+	}
+}
+
+func resourceSegmentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return createTableDefinition(ctx, d, m, "/api/segment", "Segment")
+}
+
+func resourceSegmentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readTableDefinition(ctx, d, m, "/api/segment", "Segment")
+}
+
+func resourceSegmentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return updateTableDefinition(ctx, d, m, "/api/segment", "Segment")
+}
+
+func resourceSegmentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteTableDefinition(ctx, d, m, "/api/segment", "Segment")
+}
+
+// createTableDefinition creates a segment or a legacy metric, kind names it
+// in messages and resource function names
+func createTableDefinition(ctx context.Context, d *schema.ResourceData, m interface{}, path, kind string) diag.Diagnostics {
+	c := m.(*Client)
+
+	definition := &TableDefinition{}
+	err := c.doJSON(ctx, http.MethodPost, path, expandTableDefinition(d), definition)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Table %d not found", d.Get("table_id").(int)))
+	}
+
+	d.SetId(strconv.Itoa(definition.Id))
+
+	return readTableDefinition(ctx, d, m, path, kind)
+}
+
+func readTableDefinition(ctx context.Context, d *schema.ResourceData, m interface{}, path, kind string) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	definition := &TableDefinition{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("%s/%s", path, d.Id()), nil, definition)
+	if isNotFound(err) || (err == nil && definition.Archived) {
+		tflog.Warn(ctx, fmt.Sprintf("%s is deleted or archived, removing it from state", kind), map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("%s %s not found", kind, d.Id()))
+	}
+
+	flattened, err := flattenTableDefinition(definition)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Unable to normalize JSON in resource%sRead()", kind),
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary: fmt.Sprintf("Unable to assign %s attribute to d.%s in resource%sRead()",
+					strings.ToLower(kind), k, kind),
+				Detail: err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func updateTableDefinition(ctx context.Context, d *schema.ResourceData, m interface{}, path, kind string) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("name", "description", "definition") {
+		definition := expandTableDefinition(d)
+		definition.RevisionMessage = d.Get("revision_message").(string)
+
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("%s/%s", path, d.Id()), definition, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("%s %s not found", kind, d.Id()))
+		}
+	}
+
+	return readTableDefinition(ctx, d, m, path, kind)
+}
+
+func deleteTableDefinition(ctx context.Context, d *schema.ResourceData, m interface{}, path, kind string) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// Metabase cannot delete segments and metrics, they are archived instead
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("%s/%s", path, d.Id()), map[string]interface{}{
+		"revision_message": d.Get("revision_message").(string),
+		"archived":         true,
+	}, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("%s %s not found", kind, d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandTableDefinition(d *schema.ResourceData) *TableDefinition {
+	definition := &TableDefinition{
+		Name:       d.Get("name").(string),
+		TableId:    d.Get("table_id").(int),
+		Definition: json.RawMessage(d.Get("definition").(string)),
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		s := description.(string)
+		definition.Description = &s
+	}
+
+	return definition
+}
+
+func flattenTableDefinition(definition *TableDefinition) (map[string]interface{}, error) {
+	oi := make(map[string]interface{})
+
+	oi["table_id"] = definition.TableId
+	oi["name"] = definition.Name
+	oi["description"] = ""
+	if definition.Description != nil {
+		oi["description"] = *definition.Description
+	}
+	d, err := flattenJSON(definition.Definition, "{}")
+	if err != nil {
+		return nil, err
+	}
+	oi["definition"] = d
+
+	return oi, nil
+}
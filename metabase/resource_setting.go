@@ -0,0 +1,415 @@
+package metabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// settingType describes the values a known setting accepts,
+// any value can be set to null to reset a setting to its default
+type settingType struct {
+	kind   string
+	values []string
+}
+
+var (
+	settingString  = settingType{kind: "string"}
+	settingBoolean = settingType{kind: "boolean"}
+	settingNumber  = settingType{kind: "number"}
+)
+
+// knownSettings are the settings which values are validated before
+// they are sent, other settings are passed to Metabase as is
+var knownSettings = map[string]settingType{
+	"site-name":               settingString,
+	"site-url":                settingString,
+	"site-locale":             settingString,
+	"admin-email":             settingString,
+	"report-timezone":         settingString,
+	"anon-tracking-enabled":   settingBoolean,
+	"humanization-strategy":   {kind: "string", values: []string{"simple", "none", "advanced"}},
+	"enable-public-sharing":   settingBoolean,
+	"enable-embedding":        settingBoolean,
+	"enable-nested-queries":   settingBoolean,
+	"enable-query-caching":    settingBoolean,
+	"enable-xrays":            settingBoolean,
+	"query-caching-ttl-ratio": settingNumber,
+	"start-of-week": {kind: "string", values: []string{
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	}},
+}
+
+func resourceSetting() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_setting` resource can be used for managing a single admin setting.\n\n" +
+			"Values are given as JSON, use `jsonencode` for them. " +
+			"Destroying the resource resets the setting to its default value.",
+		CreateContext: resourceSettingCreate,
+		ReadContext:   resourceSettingRead,
+		UpdateContext: resourceSettingUpdate,
+		DeleteContext: resourceSettingDelete,
+		CustomizeDiff: resourceSettingCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"key": &schema.Schema{
+				Description: "Key of a setting, e.g. `site-name` or `enable-public-sharing`",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"value": &schema.Schema{
+				Description:      "Value of a setting as JSON, e.g. `jsonencode(\"Acme\")` or `jsonencode(true)`",
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				ValidateFunc:     validation.StringIsJSON,
+				StateFunc:        settingStateFunc,
+				DiffSuppressFunc: suppressEquivalentSettingJSON,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceSettingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("key") || !d.NewValueKnown("value") {
+		return nil
+	}
+
+	return validateSetting(d.Get("key").(string), d.Get("value").(string))
+}
+
+// validateSetting returns an error when a value does not match the type of a known setting
+func validateSetting(key, value string) error {
+	t, ok := knownSettings[key]
+	if !ok {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return fmt.Errorf("value of setting %q is not valid JSON: %w", key, err)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if t.kind != "string" {
+			break
+		}
+		if len(t.values) == 0 {
+			return nil
+		}
+		for _, allowed := range t.values {
+			if v == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("setting %q must be one of %q, got %q", key, t.values, v)
+	case bool:
+		if t.kind == "boolean" {
+			return nil
+		}
+	case float64:
+		if t.kind == "number" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("setting %q must be a JSON %s or null, got %s", key, t.kind, value)
+}
+
+func resourceSettingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	key := d.Get("key").(string)
+	if diags := writeSetting(ctx, c, key, json.RawMessage(d.Get("value").(string))); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(key)
+
+	return resourceSettingRead(ctx, d, m)
+}
+
+func resourceSettingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	value, err := readSetting(ctx, c, d.Id())
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Setting is unknown to Metabase, removing it from state", map[string]interface{}{
+			"key": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Setting %s not found", d.Id()))
+	}
+
+	flattened, err := canonicalSettingJSON(string(value))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to decode setting value in resourceSettingRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	for k, v := range map[string]interface{}{"key": d.Id(), "value": flattened} {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign setting attribute to d.%s in resourceSettingRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceSettingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChange("value") {
+		if diags := writeSetting(ctx, c, d.Id(), json.RawMessage(d.Get("value").(string))); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceSettingRead(ctx, d, m)
+}
+
+func resourceSettingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	diags := writeSetting(ctx, c, d.Id(), nil)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// canonicalSettingJSON only reformats a setting value, unlike normalizeJSON
+// nothing is removed, as nulls and empty values are meaningful settings
+func canonicalSettingJSON(s string) (string, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// settingStateFunc stores setting values in their canonical form
+func settingStateFunc(v interface{}) string {
+	canonical, err := canonicalSettingJSON(v.(string))
+	if err != nil {
+		// invalid JSON is reported by validation
+		return v.(string)
+	}
+	return canonical
+}
+
+// suppressEquivalentSettingJSON suppresses diffs between setting values
+// which only differ in formatting or key order
+func suppressEquivalentSettingJSON(k, old, new string, d *schema.ResourceData) bool {
+	canonicalOld, err := canonicalSettingJSON(old)
+	if err != nil {
+		return false
+	}
+
+	canonicalNew, err := canonicalSettingJSON(new)
+	if err != nil {
+		return false
+	}
+
+	return canonicalOld == canonicalNew
+}
+
+// readSetting returns the value of a setting as JSON, an *APIError with
+// 404 status is returned for settings unknown to Metabase
+func readSetting(ctx context.Context, c *Client, key string) (json.RawMessage, error) {
+	values, err := readSettings(ctx, c, key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return nil, &APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("Setting %s not found", key)}
+	}
+	return value, nil
+}
+
+// readSettings returns the values of settings as JSON by key, settings
+// unknown to Metabase are left out. GET /api/setting/:key answers string
+// values as plain text, so a site name such as 2024 or true could not be
+// told from a number or a boolean: known settings are decoded by their kind,
+// the others are read from the list of all settings which holds JSON values.
+func readSettings(ctx context.Context, c *Client, keys ...string) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage, len(keys))
+
+	var listed map[string]json.RawMessage
+	for _, key := range keys {
+		if t, ok := knownSettings[key]; ok {
+			value, err := readKnownSetting(ctx, c, key, t)
+			if isNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			values[key] = value
+			continue
+		}
+
+		if listed == nil {
+			settings := []Setting{}
+			if err := c.doJSON(ctx, http.MethodGet, "/api/setting", nil, &settings); err != nil {
+				return nil, err
+			}
+
+			listed = make(map[string]json.RawMessage, len(settings))
+			for _, setting := range settings {
+				listed[setting.Key] = setting.Value
+			}
+		}
+
+		if value, ok := listed[key]; ok {
+			if len(value) == 0 {
+				value = json.RawMessage("null")
+			}
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// readKnownSetting reads a setting of a known kind, string values are
+// returned by Metabase as plain text and are encoded here
+func readKnownSetting(ctx context.Context, c *Client, key string, t settingType) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/setting/%s", c.HostURL, url.PathEscape(key)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Metabase-Session", c.Token)
+	// disable gzip
+	req.Header.Set("Accept-Encoding", "identity")
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	if t.kind == "string" {
+		return json.Marshal(string(body))
+	}
+
+	body = bytes.TrimSpace(body)
+	if json.Valid(body) {
+		return json.RawMessage(body), nil
+	}
+	return json.Marshal(string(body))
+}
+
+// writeSetting sets a setting, a nil value resets it to its default
+func writeSetting(ctx context.Context, c *Client, key string, value json.RawMessage) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if value == nil {
+		value = json.RawMessage("null")
+	}
+
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/setting/%s", url.PathEscape(key)), map[string]interface{}{
+		"value": value,
+	}, nil)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Setting %s not found", key))
+	}
+
+	return diags
+}
+
+// readSettingValues returns the decoded values of settings by key
+func readSettingValues(ctx context.Context, c *Client, keys ...string) (map[string]interface{}, error) {
+	raws, err := readSettings(ctx, c, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		raw, ok := raws[key]
+		if !ok {
+			// unknown to this Metabase version
+			values[key] = nil
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// settingStringValue returns a setting value as a string, null is returned as empty
+func settingStringValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// settingIntValue returns a numeric setting value, Metabase returns
+// some of them as strings
+func settingIntValue(v interface{}) int {
+	switch value := v.(type) {
+	case float64:
+		return int(value)
+	case string:
+		i, _ := strconv.Atoi(value)
+		return i
+	}
+	return 0
+}
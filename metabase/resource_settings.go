@@ -0,0 +1,185 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const settingsId = "settings"
+
+func resourceSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_settings` resource can be used for managing several admin settings at once.\n\n" +
+			"Only the listed keys are managed, other settings are left as is. Keys removed from `values` " +
+			"and all keys on destroy are reset to their default values.",
+		CreateContext: resourceSettingsCreate,
+		ReadContext:   resourceSettingsRead,
+		UpdateContext: resourceSettingsUpdate,
+		DeleteContext: resourceSettingsDelete,
+		CustomizeDiff: resourceSettingsCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"values": &schema.Schema{
+				Description: "Values of settings as JSON by key, " +
+					"e.g. `{ \"site-name\" = jsonencode(\"Acme\"), \"enable-public-sharing\" = jsonencode(false) }`",
+				Type:             schema.TypeMap,
+				Required:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressEquivalentSettingJSON,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceSettingsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("values") {
+		return nil
+	}
+
+	for key, value := range d.Get("values").(map[string]interface{}) {
+		if !json.Valid([]byte(value.(string))) {
+			return fmt.Errorf("value of setting %q is not valid JSON", key)
+		}
+		if err := validateSetting(key, value.(string)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceSettingsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	values := d.Get("values").(map[string]interface{})
+	if diags := writeSettings(ctx, c, values, nil); diags.HasError() {
+		return diags
+	}
+
+	// the managed keys change over time, they are tracked in values
+	d.SetId(settingsId)
+
+	return resourceSettingsRead(ctx, d, m)
+}
+
+func resourceSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	keys := make([]string, 0, len(d.Get("values").(map[string]interface{})))
+	for key := range d.Get("values").(map[string]interface{}) {
+		keys = append(keys, key)
+	}
+
+	raws, err := readSettings(ctx, c, keys...)
+	if err != nil {
+		return apiErrorDiags(err, "Settings not found")
+	}
+
+	values := make(map[string]interface{})
+	for _, key := range keys {
+		value, ok := raws[key]
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Setting not found",
+				Detail:   fmt.Sprintf("Metabase does not know setting %q, remove it from values", key),
+			})
+			return diags
+		}
+
+		flattened, err := canonicalSettingJSON(string(value))
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to decode setting value in resourceSettingsRead()",
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		values[key] = flattened
+	}
+
+	if err := d.Set("values", values); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to assign settings to d.values in resourceSettingsRead()",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	return diags
+}
+
+func resourceSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChange("values") {
+		old, new := d.GetChange("values")
+
+		var removed []string
+		for key := range old.(map[string]interface{}) {
+			if _, ok := new.(map[string]interface{})[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+
+		if diags := writeSettings(ctx, c, new.(map[string]interface{}), removed); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceSettingsRead(ctx, d, m)
+}
+
+func resourceSettingsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	var keys []string
+	for key := range d.Get("values").(map[string]interface{}) {
+		keys = append(keys, key)
+	}
+
+	diags := writeSettings(ctx, c, nil, keys)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// writeSettings sets values and resets the removed keys in a single call
+func writeSettings(ctx context.Context, c *Client, values map[string]interface{}, removed []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	settings := make(map[string]json.RawMessage, len(values)+len(removed))
+	for key, value := range values {
+		settings[key] = json.RawMessage(value.(string))
+	}
+	for _, key := range removed {
+		settings[key] = json.RawMessage("null")
+	}
+
+	if len(settings) == 0 {
+		return diags
+	}
+
+	err := c.doJSON(ctx, http.MethodPut, "/api/setting", settings, nil)
+	if err != nil {
+		return apiErrorDiags(err, "Settings not found")
+	}
+
+	return diags
+}
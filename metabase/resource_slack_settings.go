@@ -0,0 +1,125 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const slackSettingsId = "slack"
+
+func resourceSlackSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_slack_settings` resource can be used for connecting Metabase to a Slack app.\n\n" +
+			"Metabase checks the app token with Slack before saving it, so an apply fails when the token is rejected. " +
+			"Make Slack subscriptions and alerts depend on this resource to create them in the same run. " +
+			"Destroying the resource disconnects Slack.",
+		CreateContext: resourceSlackSettingsWrite,
+		ReadContext:   resourceSlackSettingsRead,
+		UpdateContext: resourceSlackSettingsWrite,
+		DeleteContext: resourceSlackSettingsDelete,
+		Schema: map[string]*schema.Schema{
+			"app_token": &schema.Schema{
+				Description: "Bot user OAuth token of the Slack app, starting with `xoxb-`. Metabase never returns it, " +
+					"so changes made outside of Terraform are not detected",
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"files_channel": &schema.Schema{
+				Description: "Slack channel Metabase uploads images of charts to, without the leading `#`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "metabase_files",
+			},
+			"token_valid": &schema.Schema{
+				Description: "Whether the app token was accepted by Slack the last time Metabase used it",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceSlackSettingsWrite(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodPut, "/api/slack/settings", map[string]interface{}{
+		"slack-app-token":     d.Get("app_token").(string),
+		"slack-files-channel": d.Get("files_channel").(string),
+	}, nil)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusBadRequest {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Slack app token rejected",
+			Detail: fmt.Sprintf("Slack did not accept the app token or the files channel: %s\n\n"+
+				"Make sure the token belongs to an app installed in the workspace.", apiErr.Message),
+		})
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, "Slack settings not found")
+	}
+
+	d.SetId(slackSettingsId)
+
+	return resourceSlackSettingsRead(ctx, d, m)
+}
+
+func resourceSlackSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	values, err := readSettingValues(ctx, c, "slack-files-channel", "slack-token-valid?")
+	if err != nil {
+		return apiErrorDiags(err, "Slack settings not found")
+	}
+
+	tokenValid, _ := values["slack-token-valid?"].(bool)
+	flattened := map[string]interface{}{
+		"files_channel": settingStringValue(values["slack-files-channel"]),
+		"token_valid":   tokenValid,
+	}
+
+	for k, v := range flattened {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign Slack setting to d.%s in resourceSlackSettingsRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceSlackSettingsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := c.doJSON(ctx, http.MethodPut, "/api/slack/settings", map[string]interface{}{
+		"slack-app-token": nil,
+	}, nil)
+	if err != nil {
+		return apiErrorDiags(err, "Slack settings not found")
+	}
+
+	d.SetId("")
+
+	return diags
+}
@@ -0,0 +1,271 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceTable() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_table` resource can be used for managing the metadata of a table synced by Metabase.\n\n" +
+			"The table is adopted by its database, schema and name, it is never created or deleted: " +
+			"destroying the resource only removes it from the state and keeps the metadata as is.",
+		CreateContext: resourceTableCreate,
+		ReadContext:   resourceTableRead,
+		UpdateContext: resourceTableUpdate,
+		DeleteContext: resourceTableDelete,
+		Schema: map[string]*schema.Schema{
+			"database_id": &schema.Schema{
+				Description: "ID of the database of a table",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"schema": &schema.Schema{
+				Description: "Schema of a table. Can be omitted when the table name is unique in the database",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Name of a table in the database",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"display_name": &schema.Schema{
+				Description: "Name of a table displayed by Metabase, generated from the name when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Description of a table",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"visibility_type": &schema.Schema{
+				Description:  "Visibility of a table: `hidden`, `technical` or `cruft`. The table is visible when not set",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"hidden", "technical", "cruft"}, false),
+			},
+			"entity_type": &schema.Schema{
+				Description: "Entity type of a table, e.g. `entity/UserTable` or `entity/TransactionTable`, " +
+					"detected by Metabase when not set",
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"caveats": &schema.Schema{
+				Description: "Things to be aware of about a table",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"points_of_interest": &schema.Schema{
+				Description: "Why a table is interesting",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"field_order": &schema.Schema{
+				Description:  "Order of the fields of a table: `database`, `alphabetical`, `custom` or `smart`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"database", "alphabetical", "custom", "smart"}, false),
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceTableCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	table, diags := findTable(ctx, c, d.Get("database_id").(int), d.Get("schema").(string), d.Get("name").(string))
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Info(ctx, "Adopting table", map[string]interface{}{
+		"id":     table.Id,
+		"schema": table.Schema,
+		"name":   table.Name,
+	})
+	d.SetId(strconv.Itoa(table.Id))
+
+	err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/table/%s", d.Id()), expandTable(d), nil)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Table %s not found", d.Id()))
+	}
+
+	return resourceTableRead(ctx, d, m)
+}
+
+func resourceTableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	table := &Table{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/table/%s", d.Id()), nil, table)
+	if isNotFound(err) || (err == nil && !table.Active) {
+		tflog.Warn(ctx, "Table is deleted or no longer synced, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("Table %s not found", d.Id()))
+	}
+
+	for k, v := range flattenTable(table) {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign table attribute to d.%s in resourceTableRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("display_name", "description", "visibility_type", "entity_type", "caveats", "points_of_interest", "field_order") {
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/table/%s", d.Id()), expandTable(d), nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("Table %s not found", d.Id()))
+		}
+	}
+
+	return resourceTableRead(ctx, d, m)
+}
+
+func resourceTableDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// tables belong to their database, Metabase only drops them on sync
+	tflog.Info(ctx, "Removing table from state, its metadata is kept in Metabase", map[string]interface{}{
+		"id": d.Id(),
+	})
+	d.SetId("")
+
+	return diags
+}
+
+// findTable returns the only active table of a database matching schema and name,
+// an empty schema matches any schema
+func findTable(ctx context.Context, c *Client, databaseId int, schemaName, name string) (*Table, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var tables []Table
+	if err := c.getList(ctx, "/api/table", &tables); err != nil {
+		return nil, apiErrorDiags(err, "Tables not found")
+	}
+
+	var found []Table
+	for _, table := range tables {
+		if table.DbId != databaseId || table.Name != name || !table.Active {
+			continue
+		}
+		if schemaName != "" && table.Schema != schemaName {
+			continue
+		}
+		found = append(found, table)
+	}
+
+	switch len(found) {
+	case 0:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Table not found",
+			Detail: fmt.Sprintf("No table with schema %q and name %q found in database %d, "+
+				"make sure the database is synced", schemaName, name, databaseId),
+		})
+		return nil, diags
+	case 1:
+		return &found[0], diags
+	default:
+		schemas := make([]string, len(found))
+		for i, table := range found {
+			schemas[i] = table.Schema
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Multiple tables found",
+			Detail: fmt.Sprintf("%d tables with name %q found in database %d (schemas %s), use schema to select one of them",
+				len(found), name, databaseId, strings.Join(schemas, ", ")),
+		})
+		return nil, diags
+	}
+}
+
+func expandTable(d *schema.ResourceData) *TableUpdate {
+	table := &TableUpdate{
+		DisplayName: d.Get("display_name").(string),
+		FieldOrder:  d.Get("field_order").(string),
+	}
+
+	if v, ok := d.GetOk("description"); ok {
+		s := v.(string)
+		table.Description = &s
+	}
+
+	if v, ok := d.GetOk("visibility_type"); ok {
+		s := v.(string)
+		table.VisibilityType = &s
+	}
+
+	if v, ok := d.GetOk("entity_type"); ok {
+		s := v.(string)
+		table.EntityType = &s
+	}
+
+	if v, ok := d.GetOk("caveats"); ok {
+		s := v.(string)
+		table.Caveats = &s
+	}
+
+	if v, ok := d.GetOk("points_of_interest"); ok {
+		s := v.(string)
+		table.PointsOfInterest = &s
+	}
+
+	return table
+}
+
+func flattenTable(table *Table) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	oi["database_id"] = table.DbId
+	oi["schema"] = table.Schema
+	oi["name"] = table.Name
+	oi["display_name"] = table.DisplayName
+	oi["description"] = table.Description
+	oi["visibility_type"] = table.VisibilityType
+	oi["entity_type"] = table.EntityType
+	oi["caveats"] = table.Caveats
+	oi["points_of_interest"] = table.PointsOfInterest
+	oi["field_order"] = table.FieldOrder
+
+	return oi
+}
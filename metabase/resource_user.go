@@ -0,0 +1,303 @@
+package metabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUser() *schema.Resource {
+	return &schema.Resource{
+		Description: "`metabase_user` resource can be used for managing users.\n\n" +
+			"Metabase never deletes users: destroying the resource deactivates the user, " +
+			"and creating it again with the same email reactivates the user.",
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Schema: map[string]*schema.Schema{
+			"email": &schema.Schema{
+				Description: "Email of a user, used to log in",
+				Type:        schema.TypeString,
+				Required:    true,
+				StateFunc: func(v interface{}) string {
+					return strings.ToLower(v.(string))
+				},
+			},
+			"first_name": &schema.Schema{
+				Description: "First name of a user",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"last_name": &schema.Schema{
+				Description: "Last name of a user",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"common_name": &schema.Schema{
+				Description: "Full name of a user as displayed by Metabase",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"is_superuser": &schema.Schema{
+				Description: "Whether a user is a member of the \"Administrators\" group",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"locale": &schema.Schema{
+				Description: "Locale of a user, e.g. `en` or `de`. The site locale is used when not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"login_attributes": &schema.Schema{
+				Description: "Attributes of a user used by sandboxing",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"group_ids": &schema.Schema{
+				Description: "IDs of permissions groups a user is a member of, " +
+					"except \"All Users\" and \"Administrators\" which are managed by Metabase and `is_superuser`. " +
+					"Leave it unset when memberships are managed by group membership resources",
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	email := d.Get("email").(string)
+
+	existing, err := findUserByEmail(ctx, c, email)
+	if err != nil {
+		return apiErrorDiags(err, "Users not found")
+	}
+
+	switch {
+	case existing == nil:
+		user := &User{}
+		err = c.doJSON(ctx, http.MethodPost, "/api/user", expandUser(d), user)
+		if err != nil {
+			return apiErrorDiags(err, "Unable to create user")
+		}
+		d.SetId(strconv.Itoa(user.Id))
+	case !existing.IsActive:
+		tflog.Info(ctx, "Reactivating deactivated user", map[string]interface{}{
+			"id":    existing.Id,
+			"email": email,
+		})
+		err = c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/user/%d/reactivate", existing.Id), nil, nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("User %d not found", existing.Id))
+		}
+		d.SetId(strconv.Itoa(existing.Id))
+	default:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "User already exists",
+			Detail: fmt.Sprintf("An active user with email %q already exists in Metabase with ID %d, "+
+				"import it with terraform import to manage it", email, existing.Id),
+		})
+		return diags
+	}
+
+	// is_superuser and locale are only accepted on update
+	err = c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/user/%s", d.Id()), expandUser(d), nil)
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("User %s not found", d.Id()))
+	}
+
+	return resourceUserRead(ctx, d, m)
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	user := &User{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/user/%s", d.Id()), nil, user)
+	if isNotFound(err) || (err == nil && !user.IsActive) {
+		tflog.Warn(ctx, "User is deleted or deactivated, removing it from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiags(err, fmt.Sprintf("User %s not found", d.Id()))
+	}
+
+	for k, v := range flattenUser(user) {
+		if err := d.Set(k, v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to assign user attribute to d.%s in resourceUserRead()", k),
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if d.HasChanges("email", "first_name", "last_name", "is_superuser", "locale", "login_attributes", "group_ids") {
+		err := c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/api/user/%s", d.Id()), expandUser(d), nil)
+		if err != nil {
+			return apiErrorDiags(err, fmt.Sprintf("User %s not found", d.Id()))
+		}
+	}
+
+	return resourceUserRead(ctx, d, m)
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// deactivates the user, Metabase keeps it to preserve its content
+	err := c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/user/%s", d.Id()), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return apiErrorDiags(err, fmt.Sprintf("User %s not found", d.Id()))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// findUserByEmail returns an active or a deactivated user with the email,
+// or nil when there is no such user
+func findUserByEmail(ctx context.Context, c *Client, email string) (*User, error) {
+	var users []User
+
+	query := url.Values{}
+	query.Set("include_deactivated", "true")
+
+	if err := c.getList(ctx, fmt.Sprintf("/api/user?%s", query.Encode()), &users); err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func expandUser(d *schema.ResourceData) *UserCreate {
+	user := &UserCreate{
+		Email:           d.Get("email").(string),
+		FirstName:       d.Get("first_name").(string),
+		LastName:        d.Get("last_name").(string),
+		IsSuperuser:     d.Get("is_superuser").(bool),
+		LoginAttributes: map[string]string{},
+	}
+
+	if locale, ok := d.GetOk("locale"); ok {
+		l := locale.(string)
+		user.Locale = &l
+	}
+
+	for k, v := range d.Get("login_attributes").(map[string]interface{}) {
+		user.LoginAttributes[k] = v.(string)
+	}
+
+	// memberships are replaced as a whole, so only send them when configured here
+	// and never overwrite the ones managed by group membership resources
+	if !d.GetRawConfig().GetAttr("group_ids").IsNull() {
+		groupIds := d.Get("group_ids")
+		ids := []int{allUsersGroupId}
+		if user.IsSuperuser {
+			ids = append(ids, administratorGroupId)
+		}
+		for _, id := range groupIds.(*schema.Set).List() {
+			if id.(int) != allUsersGroupId && id.(int) != administratorGroupId {
+				ids = append(ids, id.(int))
+			}
+		}
+
+		user.GroupIds = ids
+		for _, id := range ids {
+			user.UserGroupMemberships = append(user.UserGroupMemberships, UserGroupMembership{Id: id})
+		}
+	}
+
+	return user
+}
+
+func flattenUser(user *User) map[string]interface{} {
+	oi := make(map[string]interface{})
+
+	oi["email"] = user.Email
+	oi["first_name"] = user.FirstName
+	oi["last_name"] = user.LastName
+	oi["common_name"] = user.CommonName
+	oi["is_superuser"] = user.IsSuperuser
+	oi["locale"] = user.Locale
+
+	loginAttributes := make(map[string]interface{}, len(user.LoginAttributes))
+	for k, v := range user.LoginAttributes {
+		loginAttributes[k] = fmt.Sprint(v)
+	}
+	oi["login_attributes"] = loginAttributes
+
+	oi["group_ids"] = userGroupIds(user)
+
+	return oi
+}
+
+// userGroupIds returns IDs of groups a user is a member of, except the ones
+// managed by Metabase itself
+func userGroupIds(user *User) []int {
+	ids := user.GroupIds
+	for _, membership := range user.UserGroupMemberships {
+		ids = append(ids, membership.Id)
+	}
+
+	seen := make(map[int]bool)
+	groupIds := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id == allUsersGroupId || id == administratorGroupId || seen[id] {
+			continue
+		}
+		seen[id] = true
+		groupIds = append(groupIds, id)
+	}
+	sort.Ints(groupIds)
+
+	return groupIds
+}